@@ -1,30 +1,68 @@
 package gevm
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 
 	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/fetcher"
 	"github.com/bashmills/gevm/internal/environment"
-	"github.com/bashmills/gevm/internal/environment/fetcher"
 	"github.com/bashmills/gevm/internal/environment/github"
 	"github.com/bashmills/gevm/internal/locator"
-	"github.com/bashmills/gevm/internal/services/cache"
-	"github.com/bashmills/gevm/internal/services/exporttemplates"
-	"github.com/bashmills/gevm/internal/services/godot"
-	"github.com/bashmills/gevm/internal/services/settings"
-	"github.com/bashmills/gevm/internal/services/versions"
+	"github.com/bashmills/gevm/services/cache"
+	"github.com/bashmills/gevm/services/exporttemplates"
+	"github.com/bashmills/gevm/services/godot"
+	"github.com/bashmills/gevm/services/mirror"
+	"github.com/bashmills/gevm/services/settings"
+	"github.com/bashmills/gevm/services/versions"
 )
 
+const GITHUB_PRIORITY = 0
+
+type Option func(*Options)
+
+type Options struct {
+	Fetchers []Registration
+}
+
+type Registration struct {
+	Fetcher  fetcher.Fetcher
+	Priority int
+}
+
 type App struct {
 	Versions        *versions.Service
 	ExportTemplates *exporttemplates.Service
 	Godot           *godot.Service
 	Settings        *settings.Service
 	Cache           *cache.Service
+	Mirror          *mirror.Service
+	Config          *config.Config
 }
 
-func New(config *config.Config) (*App, error) {
-	environment, err := environment.New([]fetcher.Fetcher{github.New(config)}, config)
+func New(config *config.Config, options ...Option) (*App, error) {
+	opts := &Options{
+		Fetchers: []Registration{
+			{
+				Fetcher:  github.New(config),
+				Priority: GITHUB_PRIORITY,
+			},
+		},
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	slices.SortStableFunc(opts.Fetchers, func(a Registration, b Registration) int { return cmp.Compare(b.Priority, a.Priority) })
+
+	var fetchers []fetcher.Fetcher
+	for _, registration := range opts.Fetchers {
+		fetchers = append(fetchers, registration.Fetcher)
+	}
+
+	environment, err := environment.New(fetchers, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create environment: %w", err)
 	}
@@ -34,11 +72,12 @@ func New(config *config.Config) (*App, error) {
 		return nil, fmt.Errorf("failed to create locator: %w", err)
 	}
 
-	versionsService := versions.New(environment, config)
-	exportTemplatesService := exporttemplates.New(environment, config)
-	godotService := godot.New(environment, exportTemplatesService, locator, config)
+	cacheService := cache.New(environment, config)
+	exportTemplatesService := exporttemplates.New(environment, cacheService, config)
+	godotService := godot.New(environment, exportTemplatesService, locator, cacheService, config)
+	versionsService := versions.New(environment, godotService, exportTemplatesService, cacheService, config)
 	settingsService := settings.New(config)
-	cacheService := cache.New(config)
+	mirrorService := mirror.New(environment, cacheService, config)
 
 	return &App{
 		Versions:        versionsService,
@@ -46,5 +85,18 @@ func New(config *config.Config) (*App, error) {
 		Godot:           godotService,
 		Settings:        settingsService,
 		Cache:           cacheService,
+		Mirror:          mirrorService,
+		Config:          config,
 	}, nil
 }
+
+func OptionAddFetcher(fetcher fetcher.Fetcher, priority int) Option {
+	return func(options *Options) {
+		if fetcher != nil {
+			options.Fetchers = append(options.Fetchers, Registration{
+				Fetcher:  fetcher,
+				Priority: priority,
+			})
+		}
+	}
+}
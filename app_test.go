@@ -0,0 +1,72 @@
+package gevm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/internal/environment/github"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/versions"
+)
+
+func TestNewOrdersFetchers(t *testing.T) {
+	high := &fixtures.Fetcher{}
+	low := &fixtures.Fetcher{}
+
+	app, err := New(fixtures.NewConfig(t, t.TempDir()), OptionAddFetcher(low, -1), OptionAddFetcher(high, 10), OptionAddFetcher(nil, 5))
+	if err != nil {
+		t.Fatalf("cannot create app: %s", err)
+	}
+
+	fetchers := app.Versions.Environment.Fetchers
+	if len(fetchers) != 3 {
+		t.Fatalf("expected three fetchers but got: %v", fetchers)
+	}
+
+	_, ok := fetchers[1].(*github.Github)
+	if fetchers[0] != fetcher.Fetcher(high) || !ok || fetchers[2] != fetcher.Fetcher(low) {
+		t.Errorf("expected fetchers in order of priority but got: %v", fetchers)
+	}
+}
+
+func TestNewReturnsData(t *testing.T) {
+	server := fixtures.NewServer(t, nil)
+
+	download := fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: server.URL + "/Godot_v4.3-stable_linux.x86_64.zip"})
+	download.Notes = "Release notes"
+
+	config := fixtures.NewConfig(t, t.TempDir())
+	config.SourceURL = server.URL
+
+	app, err := New(config, OptionAddFetcher(&fixtures.Fetcher{Downloads: []repository.Download{download}}, 10))
+	if err != nil {
+		t.Fatalf("cannot create app: %s", err)
+	}
+
+	downloads, err := app.Versions.Available(context.Background(), versions.Filter{})
+	if err != nil {
+		t.Fatalf("cannot list versions: %s", err)
+	}
+
+	if len(downloads) != 1 || !downloads[0].Relver.Equal(download.Relver) {
+		t.Errorf("expected the registered download but got: %v", downloads)
+	}
+
+	release, err := app.Versions.Info(context.Background(), download.Relver)
+	if err != nil {
+		t.Fatalf("cannot fetch release: %s", err)
+	}
+
+	if release.Notes != "Release notes" || !release.Standard.HasAsset(platform.LinuxAmd64) {
+		t.Errorf("expected release details but got: %v", release)
+	}
+
+	installed, err := app.Godot.IsInstalled(semver.Maybe("4.3", "stable", false))
+	if err != nil || installed {
+		t.Errorf("expected nothing to be installed but got: %t %v", installed, err)
+	}
+}
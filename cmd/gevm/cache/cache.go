@@ -1,9 +1,16 @@
 package cache
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/internal/output"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/cache"
 )
 
 type Clear struct{}
@@ -17,6 +24,154 @@ func (c *Clear) Run(app *gevm.App) error {
 	return nil
 }
 
+type List struct{}
+
+func (c *List) Run(app *gevm.App) error {
+	archives, err := app.Cache.Archives()
+	if err != nil {
+		return fmt.Errorf("cannot list cache: %w", err)
+	}
+
+	format := output.Format(app.Config.Output)
+	if len(archives) == 0 && format == output.TABLE {
+		app.Config.Logger.Info("No archives cached")
+		return nil
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "name", Title: "Name"},
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "mono", Title: "Mono?"},
+			{Key: "platforms", Title: "Platform"},
+			{Key: "size", Title: "Size", Formatter: formatSize},
+			{Key: "downloaded", Title: "Downloaded", Formatter: formatTime},
+			{Key: "installed", Title: "Installed?"},
+		},
+	}
+
+	var total int64
+	for _, archive := range archives {
+		var platforms []string
+		for _, platform := range archive.Platforms {
+			platforms = append(platforms, string(platform))
+		}
+
+		version := archive.Semver.Relver.Version.String()
+		release := archive.Semver.Relver.Release.String()
+		mono := archive.Semver.Mono
+
+		t.AppendRow(archive.Name, version, release, mono, platforms, archive.Size, archive.Downloaded, archive.Installed)
+		total += archive.Size
+	}
+
+	t.Footer = []any{"Total", "", "", "", "", utils.FormatBytes(total), "", ""}
+	t.Summary = []output.Field{{Key: "total", Value: total}}
+	t.Items = "archives"
+
+	err = output.Render(format, os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render archives: %w", err)
+	}
+
+	return nil
+}
+
+type Prune struct {
+	MaxSize       string `help:"Remove the oldest archives until the cache is below this size (e.g. 500M, 20G)"`
+	OlderThan     string `help:"Remove archives downloaded longer ago than this (e.g. 12h, 90d, 2w)"`
+	KeepInstalled bool   `default:"true" negatable:"" help:"Never remove archives of installed versions"`
+	KeepLatest    int    `placeholder:"N" help:"Keep the latest N archives per minor version and remove the rest"`
+}
+
+func (c *Prune) Run(app *gevm.App) error {
+	policy := cache.Policy{
+		KeepInstalled: c.KeepInstalled,
+		KeepLatest:    c.KeepLatest,
+	}
+
+	if len(c.MaxSize) > 0 {
+		maxSize, err := utils.ParseBytes(c.MaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid max size: %w", err)
+		}
+
+		policy.MaxSize = maxSize
+	}
+
+	if len(c.OlderThan) > 0 {
+		olderThan, err := utils.ParseDuration(c.OlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid older than: %w", err)
+		}
+
+		policy.OlderThan = olderThan
+	}
+
+	err := app.Cache.Prune(policy)
+	if err != nil {
+		return fmt.Errorf("cannot prune cache: %w", err)
+	}
+
+	return nil
+}
+
+type Export struct {
+	Bundle   string   `arg:"" help:"Path of the bundle file to create"`
+	Versions []string `arg:"" help:"Versions to export in the format x.x.x.x, x.x.x or x.x (optionally with a release and mono suffix such as 4.3-rc2-mono)"`
+	Release  string   `short:"r" default:"stable" help:"Release to use for versions without one (dev1, alpha2, beta3, rc4, stable, etc)"`
+	Mono     bool     `short:"m" help:"Use mono versions"`
+}
+
+func (c *Export) Run(ctx context.Context, app *gevm.App) error {
+	var semvers []semver.Semver
+	for _, version := range c.Versions {
+		result, err := semver.Parse(version)
+		if err != nil {
+			result, err = semver.New(version, c.Release, c.Mono)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid version: %w", err)
+		}
+
+		result.Mono = result.Mono || c.Mono
+		semvers = append(semvers, result)
+	}
+
+	err := app.Cache.Export(ctx, c.Bundle, semvers)
+	if err != nil {
+		return fmt.Errorf("cannot export cache: %w", err)
+	}
+
+	return nil
+}
+
+type Import struct {
+	Bundle string `arg:"" type:"existingfile" help:"Path of the bundle file to import"`
+}
+
+func (c *Import) Run(ctx context.Context, app *gevm.App) error {
+	err := app.Cache.Import(ctx, c.Bundle)
+	if err != nil {
+		return fmt.Errorf("cannot import cache: %w", err)
+	}
+
+	return nil
+}
+
+func formatSize(value any) string {
+	return utils.FormatBytes(value.(int64))
+}
+
+func formatTime(value any) string {
+	return value.(time.Time).Format(time.DateTime)
+}
+
 type Cache struct {
-	Clear Clear `cmd:"" help:"Clear the cache"`
+	Clear  Clear  `cmd:"" help:"Clear the cache"`
+	List   List   `cmd:"" help:"List all cached archives"`
+	Prune  Prune  `cmd:"" help:"Remove cached archives according to a policy"`
+	Export Export `cmd:"" help:"Export cached archives and release indexes to a bundle"`
+	Import Import `cmd:"" help:"Import a bundle into the cache"`
 }
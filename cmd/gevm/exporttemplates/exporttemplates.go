@@ -1,9 +1,14 @@
 package exporttemplates
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/output"
 	"github.com/bashmills/gevm/semver"
 )
 
@@ -13,8 +18,8 @@ type Download struct {
 	Mono    bool   `short:"m" help:"Use mono version"`
 }
 
-func (c *Download) Run(app *gevm.App) error {
-	err := app.ExportTemplates.Download(semver.Maybe(c.Version, c.Release, c.Mono))
+func (c *Download) Run(ctx context.Context, app *gevm.App) error {
+	err := app.ExportTemplates.Download(ctx, semver.Maybe(c.Version, c.Release, c.Mono))
 	if err != nil {
 		return fmt.Errorf("cannot download export templates: %w", err)
 	}
@@ -28,8 +33,8 @@ type Uninstall struct {
 	Mono    bool   `short:"m" help:"Use mono version"`
 }
 
-func (c *Uninstall) Run(app *gevm.App) error {
-	err := app.ExportTemplates.Uninstall(semver.Maybe(c.Version, c.Release, c.Mono), true)
+func (c *Uninstall) Run(ctx context.Context, app *gevm.App) error {
+	err := app.ExportTemplates.Uninstall(ctx, semver.Maybe(c.Version, c.Release, c.Mono))
 	if err != nil {
 		return fmt.Errorf("cannot uninstall export templates: %w", err)
 	}
@@ -38,13 +43,20 @@ func (c *Uninstall) Run(app *gevm.App) error {
 }
 
 type Install struct {
-	Version string `arg:"" help:"Export templates version to download and install in the format x.x.x.x, x.x.x or x.x"`
-	Release string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
-	Mono    bool   `short:"m" help:"Use mono version"`
+	Version string   `arg:"" help:"Export templates version to download and install in the format x.x.x.x, x.x.x or x.x"`
+	Release string   `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
+	Mono    bool     `short:"m" help:"Use mono version"`
+	Only    []string `short:"o" sep:"," help:"Only install templates for these platforms (android, ios, linux, macos, uwp, web, windows)"`
 }
 
-func (c *Install) Run(app *gevm.App) error {
-	err := app.ExportTemplates.Install(semver.Maybe(c.Version, c.Release, c.Mono))
+func (c *Install) Run(ctx context.Context, app *gevm.App) error {
+	semver := semver.Maybe(c.Version, c.Release, c.Mono)
+
+	err := app.ExportTemplates.Install(ctx, semver, c.Only)
+	if errors.Is(err, errs.ErrAlreadyInstalled) {
+		app.Config.Logger.Info("Export templates '%s' already installed", semver.ExportTemplatesString())
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("cannot install export templates: %w", err)
 	}
@@ -55,18 +67,46 @@ func (c *Install) Run(app *gevm.App) error {
 type List struct{}
 
 func (c *List) Run(app *gevm.App) error {
-	err := app.ExportTemplates.List()
+	installations, err := app.ExportTemplates.ListInstalled()
 	if err != nil {
 		return fmt.Errorf("cannot list export templates: %w", err)
 	}
 
+	format := output.Format(app.Config.Output)
+	if len(installations) == 0 && format == output.TABLE {
+		app.Config.Logger.Info("No export templates installed")
+		return nil
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "mono", Title: "Mono?"},
+			{Key: "platforms", Title: "Platforms"},
+		},
+	}
+
+	for _, installation := range installations {
+		version := installation.Semver.Relver.Version.String()
+		release := installation.Semver.Relver.Release.String()
+		mono := installation.Semver.Mono
+
+		t.AppendRow(version, release, mono, installation.Platforms)
+	}
+
+	err = output.Render(format, os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render export templates: %w", err)
+	}
+
 	return nil
 }
 
 type Clear struct{}
 
-func (c *Clear) Run(app *gevm.App) error {
-	err := app.ExportTemplates.Clear()
+func (c *Clear) Run(ctx context.Context, app *gevm.App) error {
+	err := app.ExportTemplates.Clear(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot clear export templates: %w", err)
 	}
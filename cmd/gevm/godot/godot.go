@@ -1,9 +1,16 @@
 package godot
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/output"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
 	"github.com/bashmills/gevm/semver"
 )
 
@@ -12,17 +19,28 @@ type Download struct {
 	ExcludeExportTemplates bool   `short:"e" help:"Exclude export templates in download"`
 	Release                string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
 	Mono                   bool   `short:"m" help:"Use mono version"`
+	Platform               string `short:"p" help:"Platform to download for such as \"Windows Amd64\" or windows/amd64 (defaults to the current platform)"`
 }
 
-func (c *Download) Run(app *gevm.App) error {
+func (c *Download) Run(ctx context.Context, app *gevm.App) error {
+	target := app.Config.Platform
+	if len(c.Platform) > 0 {
+		result, err := platform.ParseEngine(c.Platform)
+		if err != nil {
+			return fmt.Errorf("cannot parse platform: %w", err)
+		}
+
+		target = result
+	}
+
 	if !c.ExcludeExportTemplates {
-		err := app.ExportTemplates.Download(semver.Maybe(c.Version, c.Release, c.Mono))
+		err := app.ExportTemplates.Download(ctx, semver.Maybe(c.Version, c.Release, c.Mono))
 		if err != nil {
 			return fmt.Errorf("cannot download export templates: %w", err)
 		}
 	}
 
-	err := app.Godot.Download(semver.Maybe(c.Version, c.Release, c.Mono))
+	err := app.Godot.Download(ctx, semver.Maybe(c.Version, c.Release, c.Mono), target)
 	if err != nil {
 		return fmt.Errorf("cannot download godot: %w", err)
 	}
@@ -37,15 +55,15 @@ type Uninstall struct {
 	Mono                   bool   `short:"m" help:"Use mono version"`
 }
 
-func (c *Uninstall) Run(app *gevm.App) error {
+func (c *Uninstall) Run(ctx context.Context, app *gevm.App) error {
 	if !c.ExcludeExportTemplates {
-		err := app.ExportTemplates.Uninstall(semver.Maybe(c.Version, c.Release, c.Mono), false)
-		if err != nil {
+		err := app.ExportTemplates.Uninstall(ctx, semver.Maybe(c.Version, c.Release, c.Mono))
+		if !errors.Is(err, errs.ErrNotInstalled) && err != nil {
 			return fmt.Errorf("cannot uninstall export templates: %w", err)
 		}
 	}
 
-	err := app.Godot.Uninstall(semver.Maybe(c.Version, c.Release, c.Mono), true)
+	err := app.Godot.Uninstall(ctx, semver.Maybe(c.Version, c.Release, c.Mono))
 	if err != nil {
 		return fmt.Errorf("cannot uninstall godot: %w", err)
 	}
@@ -54,21 +72,30 @@ func (c *Uninstall) Run(app *gevm.App) error {
 }
 
 type Install struct {
-	Version                string `arg:"" help:"Godot engine version to download and install in the format x.x.x.x, x.x.x or x.x"`
-	ExcludeExportTemplates bool   `short:"e" help:"Exclude export templates in install"`
-	Release                string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
-	Mono                   bool   `short:"m" help:"Use mono version"`
+	Version                string   `arg:"" help:"Godot engine version to download and install in the format x.x.x.x, x.x.x or x.x"`
+	ExcludeExportTemplates bool     `short:"e" help:"Exclude export templates in install"`
+	Release                string   `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
+	Mono                   bool     `short:"m" help:"Use mono version"`
+	Only                   []string `short:"o" sep:"," help:"Only install export templates for these platforms (android, ios, linux, macos, uwp, web, windows)"`
 }
 
-func (c *Install) Run(app *gevm.App) error {
+func (c *Install) Run(ctx context.Context, app *gevm.App) error {
+	semver := semver.Maybe(c.Version, c.Release, c.Mono)
+
 	if !c.ExcludeExportTemplates {
-		err := app.ExportTemplates.Install(semver.Maybe(c.Version, c.Release, c.Mono))
-		if err != nil {
+		err := app.ExportTemplates.Install(ctx, semver, c.Only)
+		if errors.Is(err, errs.ErrAlreadyInstalled) {
+			app.Config.Logger.Info("Export templates '%s' already installed", semver.ExportTemplatesString())
+		} else if err != nil {
 			return fmt.Errorf("cannot install export templates: %w", err)
 		}
 	}
 
-	err := app.Godot.Install(semver.Maybe(c.Version, c.Release, c.Mono))
+	err := app.Godot.Install(ctx, semver)
+	if errors.Is(err, errs.ErrAlreadyInstalled) {
+		app.Config.Logger.Info("Godot '%s' already installed", semver.GodotString())
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("cannot install godot: %w", err)
 	}
@@ -77,15 +104,57 @@ func (c *Install) Run(app *gevm.App) error {
 }
 
 type Path struct {
-	Version string `arg:"" help:"Godot engine version to use in the format x.x.x.x, x.x.x or x.x"`
-	Release string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
-	Mono    bool   `short:"m" help:"Use mono version"`
+	Version  string `arg:"" help:"Godot engine version to use in the format x.x.x.x, x.x.x or x.x"`
+	Release  string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
+	Mono     bool   `short:"m" help:"Use mono version"`
+	Console  bool   `help:"Locate the console executable where the platform ships one (Windows)"`
+	Platform string `short:"p" help:"Platform layout to locate such as \"Darwin Arm64\" or darwin/arm64 (defaults to the current platform)"`
+	Raw      bool   `help:"Print only the path without a trailing newline"`
 }
 
 func (c *Path) Run(app *gevm.App) error {
-	err := app.Godot.Path(semver.Maybe(c.Version, c.Release, c.Mono))
+	semver := semver.Maybe(c.Version, c.Release, c.Mono)
+
+	target := app.Config.Platform
+	if len(c.Platform) > 0 {
+		result, err := platform.ParseEngine(c.Platform)
+		if err != nil {
+			return fmt.Errorf("cannot parse platform: %w", err)
+		}
+
+		target = result
+	}
+
+	path, err := app.Godot.Path(semver, target, c.Console)
 	if err != nil {
-		return fmt.Errorf("cannot print path: %w", err)
+		return fmt.Errorf("cannot determine path: %w", err)
+	}
+
+	if c.Raw {
+		fmt.Print(path)
+		return nil
+	}
+
+	format := output.Format(app.Config.Output)
+	if format == output.TABLE {
+		utils.Printlnf(path)
+		return nil
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "mono", Title: "Mono?"},
+			{Key: "path", Title: "Path"},
+		},
+	}
+
+	t.AppendRow(semver.Relver.Version, semver.Relver.Release, semver.Mono, path)
+
+	err = output.Render(format, os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render path: %w", err)
 	}
 
 	return nil
@@ -94,11 +163,39 @@ func (c *Path) Run(app *gevm.App) error {
 type List struct{}
 
 func (c *List) Run(app *gevm.App) error {
-	err := app.Godot.List()
+	installations, err := app.Godot.ListInstalled()
 	if err != nil {
 		return fmt.Errorf("cannot list godot: %w", err)
 	}
 
+	format := output.Format(app.Config.Output)
+	if len(installations) == 0 && format == output.TABLE {
+		app.Config.Logger.Info("No godot engine versions installed")
+		return nil
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "export-templates", Title: "Export Templates?"},
+			{Key: "mono", Title: "Mono?"},
+		},
+	}
+
+	for _, installation := range installations {
+		version := installation.Semver.Relver.Version.String()
+		release := installation.Semver.Relver.Release.String()
+		mono := installation.Semver.Mono
+
+		t.AppendRow(version, release, installation.ExportTemplates, mono)
+	}
+
+	err = output.Render(format, os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render godot versions: %w", err)
+	}
+
 	return nil
 }
 
@@ -106,15 +203,15 @@ type Clear struct {
 	ExcludeExportTemplates bool `short:"e" help:"Exclude export templates in uninstall"`
 }
 
-func (c *Clear) Run(app *gevm.App) error {
+func (c *Clear) Run(ctx context.Context, app *gevm.App) error {
 	if !c.ExcludeExportTemplates {
-		err := app.ExportTemplates.Clear()
+		err := app.ExportTemplates.Clear(ctx)
 		if err != nil {
 			return fmt.Errorf("cannot clear export templates: %w", err)
 		}
 	}
 
-	err := app.Godot.Clear()
+	err := app.Godot.Clear(ctx)
 	if err != nil {
 		return fmt.Errorf("cannot clear godot: %w", err)
 	}
@@ -1,18 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/alecthomas/kong"
 	"github.com/bashmills/gevm"
 	"github.com/bashmills/gevm/cmd/gevm/cache"
 	"github.com/bashmills/gevm/cmd/gevm/exporttemplates"
 	"github.com/bashmills/gevm/cmd/gevm/godot"
+	"github.com/bashmills/gevm/cmd/gevm/mirror"
 	"github.com/bashmills/gevm/cmd/gevm/settings"
 	"github.com/bashmills/gevm/cmd/gevm/version"
 	"github.com/bashmills/gevm/cmd/gevm/versions"
 	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
 	"github.com/bashmills/gevm/internal/logging"
+	"github.com/bashmills/gevm/internal/utils"
+)
+
+const (
+	EXIT_FAILURE           = 1
+	EXIT_VERSION_NOT_FOUND = 3
+	EXIT_ASSET_NOT_FOUND   = 4
+	EXIT_NOT_INSTALLED     = 5
+	EXIT_ALREADY_INSTALLED = 6
+	EXIT_NETWORK           = 7
+	EXIT_CHECKSUM_MISMATCH = 8
+	EXIT_SETTING_NOT_FOUND = 9
+	EXIT_LOCK_TIMEOUT      = 10
+	EXIT_INTERRUPTED       = 130
 )
 
 var CLI struct {
@@ -21,15 +42,29 @@ var CLI struct {
 	Godot           godot.Godot                     `cmd:"" help:"Run commands related to godot engines"`
 	Settings        settings.Settings               `cmd:"" help:"View and adjust config settings"`
 	Cache           cache.Cache                     `cmd:"" help:"Run commands on the cache"`
+	Mirror          mirror.Mirror                   `cmd:"" help:"Run commands related to the release mirror"`
 	Version         version.Version                 `cmd:"" help:"Print current version"`
 
 	LoggingLevel string `short:"l" enum:"nothing,error,warning,info,debug,trace" default:"info" help:"Which log level to use"`
+	LogFormat    string `enum:"text,json" default:"text" help:"Which log format to use"`
+	LogFile      string `type:"path" help:"Write logs to this file instead of stderr"`
 	ConfigPath   string `help:"Override which config path to use"`
 	Silent       bool   `help:"Prevent progress bar log spam"`
+	Output       string `enum:"table,json,yaml,csv,markdown" default:"table" help:"Which output format to use for listings"`
 }
 
 func main() {
-	ctx := kong.Parse(&CLI)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Restore the default signal handling once the first signal arrives so a
+	// second Ctrl-C kills the process even if cleanup hangs.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	parser := kong.Parse(&CLI, kong.BindTo(ctx, (*context.Context)(nil)))
 
 	var level logging.Level
 	switch CLI.LoggingLevel {
@@ -49,7 +84,21 @@ func main() {
 		level = logging.TRACE
 	}
 
-	logger, err := logging.New(level)
+	options := []logging.Option{
+		logging.OptionSetFormat(logging.Format(CLI.LogFormat)),
+	}
+
+	if CLI.LogFile != "" {
+		file, err := os.OpenFile(CLI.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, utils.OS_FILE)
+		if err != nil {
+			log.Fatalf("failed to open log file: %s", err)
+		}
+		defer file.Close()
+
+		options = append(options, logging.OptionSetWriter(file))
+	}
+
+	logger, err := logging.New(level, options...)
 	if err != nil {
 		log.Fatalf("failed to create logger: %s", err)
 	}
@@ -58,6 +107,7 @@ func main() {
 		config.OptionSetConfigPath(CLI.ConfigPath),
 		config.OptionSetSilent(CLI.Silent),
 		config.OptionSetLogger(logger),
+		config.OptionSetOutput(CLI.Output),
 	)
 	if err != nil {
 		log.Fatalf("failed to create config: %s", err)
@@ -68,8 +118,34 @@ func main() {
 		log.Fatalf("failed to create app: %s", err)
 	}
 
-	err = ctx.Run(app)
+	err = parser.Run(app)
 	if err != nil {
-		log.Fatalf("failed to run: %s", err)
+		logger.Error("%s", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return EXIT_INTERRUPTED
+	case errors.Is(err, errs.ErrVersionNotFound):
+		return EXIT_VERSION_NOT_FOUND
+	case errors.Is(err, errs.ErrAssetNotFound):
+		return EXIT_ASSET_NOT_FOUND
+	case errors.Is(err, errs.ErrNotInstalled):
+		return EXIT_NOT_INSTALLED
+	case errors.Is(err, errs.ErrAlreadyInstalled):
+		return EXIT_ALREADY_INSTALLED
+	case errors.Is(err, errs.ErrNetwork):
+		return EXIT_NETWORK
+	case errors.Is(err, errs.ErrChecksumMismatch):
+		return EXIT_CHECKSUM_MISMATCH
+	case errors.Is(err, errs.ErrSettingNotFound):
+		return EXIT_SETTING_NOT_FOUND
+	case errors.Is(err, errs.ErrLockTimeout):
+		return EXIT_LOCK_TIMEOUT
 	}
+
+	return EXIT_FAILURE
 }
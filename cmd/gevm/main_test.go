@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bashmills/gevm/errs"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		Err      error
+		Expected int
+	}{
+		{Err: errors.New("invalid argument"), Expected: EXIT_FAILURE},
+		{Err: fmt.Errorf("install failed: %w", errs.ErrVersionNotFound), Expected: EXIT_VERSION_NOT_FOUND},
+		{Err: fmt.Errorf("install failed: %w", &errs.AssetNotFoundError{Platform: "linux-amd64", Version: "4.3-stable"}), Expected: EXIT_ASSET_NOT_FOUND},
+		{Err: fmt.Errorf("uninstall failed: %w", errs.ErrNotInstalled), Expected: EXIT_NOT_INSTALLED},
+		{Err: errs.ErrAlreadyInstalled, Expected: EXIT_ALREADY_INSTALLED},
+		{Err: fmt.Errorf("fetch failed: %w", errs.ErrNetwork), Expected: EXIT_NETWORK},
+		{Err: fmt.Errorf("import failed: %w", errs.ErrChecksumMismatch), Expected: EXIT_CHECKSUM_MISMATCH},
+		{Err: fmt.Errorf("get failed: %w", errs.ErrSettingNotFound), Expected: EXIT_SETTING_NOT_FOUND},
+		{Err: fmt.Errorf("cannot lock: %w", errs.ErrLockTimeout), Expected: EXIT_LOCK_TIMEOUT},
+		{Err: fmt.Errorf("fetch failed: %w: %w", errs.ErrNetwork, context.Canceled), Expected: EXIT_INTERRUPTED},
+	}
+
+	for _, test := range tests {
+		result := exitCode(test.Err)
+		if result != test.Expected {
+			t.Errorf("expected exit code %d for '%s' but got %d", test.Expected, test.Err, result)
+		}
+	}
+}
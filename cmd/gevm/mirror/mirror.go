@@ -0,0 +1,117 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/internal/output"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/mirror"
+)
+
+type Serve struct {
+	Address   string `short:"a" default:":8080" help:"Address to listen on"`
+	URL       string `short:"u" help:"Base url used for download links (otherwise derived from each request)"`
+	Directory string `short:"d" type:"path" help:"Mirror directory populated by sync to serve (otherwise serves the cache directory)"`
+}
+
+func (c *Serve) Run(ctx context.Context, app *gevm.App) error {
+	err := app.Mirror.Serve(ctx, c.Address, c.URL, c.Directory)
+	if err != nil {
+		return fmt.Errorf("cannot serve mirror: %w", err)
+	}
+
+	return nil
+}
+
+type Sync struct {
+	Directory   string   `arg:"" type:"path" help:"Mirror directory to populate"`
+	Constraints []string `arg:"" optional:"" help:"Version constraints to sync (e.g. 4.3, >=4.2, ~4.2.1, ^4, \">=3.5, <4\")"`
+	All         bool     `short:"a" help:"Sync all releases (otherwise only sync stable releases)"`
+	Standard    bool     `default:"true" negatable:"" help:"Sync standard versions"`
+	Mono        bool     `short:"m" help:"Sync mono versions"`
+	Platforms   []string `short:"p" help:"Platforms to sync (defaults to every platform including export templates)"`
+}
+
+func (c *Sync) Run(ctx context.Context, app *gevm.App) error {
+	var constraints []semver.Constraint
+	for _, value := range c.Constraints {
+		constraint, err := semver.ParseConstraint(value)
+		if err != nil {
+			return fmt.Errorf("invalid constraint: %w", err)
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	platforms := platform.Platforms
+	if len(c.Platforms) > 0 {
+		platforms = nil
+		for _, value := range c.Platforms {
+			result, err := platform.Parse(value)
+			if err != nil {
+				return fmt.Errorf("cannot parse platform: %w", err)
+			}
+
+			platforms = append(platforms, result)
+		}
+	}
+
+	entries, err := app.Mirror.Sync(ctx, c.Directory, mirror.SyncOptions{
+		Constraints: constraints,
+		All:         c.All,
+		Standard:    c.Standard,
+		Mono:        c.Mono,
+		Platforms:   platforms,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot sync mirror: %w", err)
+	}
+
+	format := output.Format(app.Config.Output)
+	if len(entries) == 0 && format == output.TABLE {
+		app.Config.Logger.Info("No assets matched")
+		return nil
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "name", Title: "Name"},
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "mono", Title: "Mono?"},
+			{Key: "platforms", Title: "Platform"},
+			{Key: "size", Title: "Size", Formatter: formatSize},
+			{Key: "fetched", Title: "Fetched?"},
+		},
+	}
+
+	for _, entry := range entries {
+		var platforms []string
+		for _, platform := range entry.Platforms {
+			platforms = append(platforms, string(platform))
+		}
+
+		t.AppendRow(entry.Name, entry.Version, entry.Release, entry.Mono, platforms, entry.Size, entry.Fetched)
+	}
+
+	err = output.Render(format, os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render entries: %w", err)
+	}
+
+	return nil
+}
+
+type Mirror struct {
+	Serve Serve `cmd:"" help:"Serve cached archives as a release mirror for other gevm instances"`
+	Sync  Sync  `cmd:"" help:"Download matching releases into a mirror directory"`
+}
+
+func formatSize(value any) string {
+	return utils.FormatBytes(value.(int64))
+}
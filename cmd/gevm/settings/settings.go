@@ -2,8 +2,11 @@ package settings
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/internal/output"
+	"github.com/bashmills/gevm/internal/utils"
 )
 
 type Reset struct{}
@@ -20,9 +23,11 @@ func (c *Reset) Run(app *gevm.App) error {
 type List struct{}
 
 func (c *List) Run(app *gevm.App) error {
-	err := app.Settings.List()
+	settings := app.Settings.Settings()
+
+	err := render(app, app.Settings.Keys(), settings)
 	if err != nil {
-		return fmt.Errorf("cannot list settings: %w", err)
+		return fmt.Errorf("cannot render settings: %w", err)
 	}
 
 	return nil
@@ -34,36 +39,78 @@ type Set struct {
 }
 
 func (c *Set) Run(app *gevm.App) error {
-	err := app.Settings.Set(c.Key, c.Value)
+	previous, err := app.Settings.Get(c.Key)
+	if err != nil {
+		return fmt.Errorf("cannot get setting: %w", err)
+	}
+
+	err = app.Settings.Set(c.Key, c.Value)
 	if err != nil {
 		return fmt.Errorf("cannot set setting: %w", err)
 	}
 
+	current, err := app.Settings.Get(c.Key)
+	if err != nil {
+		return fmt.Errorf("cannot get setting: %w", err)
+	}
+
+	utils.Printlnf("%s = %s => %s", c.Key, previous, current)
 	return nil
 }
 
 type Get struct {
 	Key string `arg:"" help:"Key to get config value for"`
+	Raw bool   `help:"Print only the value without a trailing newline"`
 }
 
 func (c *Get) Run(app *gevm.App) error {
-	err := app.Settings.Get(c.Key)
+	value, err := app.Settings.Get(c.Key)
 	if err != nil {
 		return fmt.Errorf("cannot get setting: %w", err)
 	}
 
+	if c.Raw {
+		fmt.Print(value)
+		return nil
+	}
+
+	err = render(app, []string{c.Key}, map[string]string{c.Key: value})
+	if err != nil {
+		return fmt.Errorf("cannot render setting: %w", err)
+	}
+
 	return nil
 }
 
 type Path struct{}
 
 func (c *Path) Run(app *gevm.App) error {
-	err := app.Settings.Path()
-	if err != nil {
-		return fmt.Errorf("cannot print path: %w", err)
+	utils.Printlnf(app.Settings.Path())
+	return nil
+}
+
+func render(app *gevm.App, keys []string, settings map[string]string) error {
+	format := output.Format(app.Config.Output)
+	if format == output.TABLE {
+		for _, key := range keys {
+			utils.Printlnf("%s = %s", key, settings[key])
+		}
+
+		return nil
 	}
 
-	return nil
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "key", Title: "Key"},
+			{Key: "value", Title: "Value"},
+		},
+	}
+
+	for _, key := range keys {
+		t.AppendRow(key, settings[key])
+	}
+
+	return output.Render(format, os.Stdout, t)
 }
 
 type Settings struct {
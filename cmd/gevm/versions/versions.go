@@ -1,9 +1,19 @@
 package versions
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/bashmills/gevm"
+	"github.com/bashmills/gevm/internal/output"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/versions"
 )
 
 type Detailed struct {
@@ -11,30 +21,324 @@ type Detailed struct {
 	Mono bool `short:"m" help:"View mono versions"`
 }
 
-func (c *Detailed) Run(app *gevm.App) error {
-	err := app.Versions.Detailed(c.All, c.Mono)
+func (c *Detailed) Run(ctx context.Context, app *gevm.App) error {
+	downloads, err := app.Versions.Available(ctx, versions.Filter{
+		All:  c.All,
+		Mono: c.Mono,
+	})
 	if err != nil {
 		return fmt.Errorf("cannot view detailed versions: %w", err)
 	}
 
+	statuses, err := app.Versions.Statuses(semversOf(downloads, c.Mono), app.Config.Platform)
+	if err != nil {
+		return fmt.Errorf("cannot determine version statuses: %w", err)
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+		},
+	}
+
+	for _, platform := range platform.Platforms {
+		t.Columns = append(t.Columns, output.Column{Key: platform.Slug(), Title: string(platform)})
+	}
+
+	t.Columns = append(t.Columns, statusColumns...)
+
+	for index, download := range downloads {
+		row := []any{
+			download.Relver.Version,
+			download.Relver.Release,
+		}
+
+		for _, platform := range platform.Platforms {
+			row = append(row, download.HasAsset(platform))
+		}
+
+		appendStatusRow(t, statuses[index], row...)
+	}
+
+	err = output.Render(output.Format(app.Config.Output), os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render versions: %w", err)
+	}
+
 	return nil
 }
 
 type List struct {
-	All  bool `short:"a" help:"List all versions (otherwise only list stable versions)"`
-	Mono bool `short:"m" help:"List mono versions"`
+	Constraints    []string `arg:"" optional:"" help:"Version constraints to list (e.g. 4.3, >=4.2, ~4.2.1, ^4)"`
+	All            bool     `short:"a" help:"List all versions (otherwise only list stable versions)"`
+	Mono           bool     `short:"m" xor:"mono" help:"List mono versions"`
+	Combined       bool     `short:"c" xor:"mono" help:"List standard and mono availability side by side"`
+	Label          []string `help:"Only list these release labels (dev, alpha, beta, rc, stable)"`
+	Since          string   `placeholder:"YYYY-MM-DD" help:"Only list versions published on or after this date"`
+	Limit          int      `placeholder:"N" help:"Only list the latest N versions"`
+	LatestPerMinor bool     `help:"Only list the latest version of each minor version"`
+	Installed      bool     `xor:"installed" help:"Only list installed versions"`
+	NotInstalled   bool     `xor:"installed" help:"Only list versions that are not installed"`
+	Platform       string   `short:"p" help:"Platform to show availability for such as \"Windows Amd64\" or windows/amd64 (defaults to the current platform)"`
 }
 
-func (c *List) Run(app *gevm.App) error {
-	err := app.Versions.List(c.All, c.Mono)
+func (c *List) Run(ctx context.Context, app *gevm.App) error {
+	filter := versions.Filter{
+		All:            c.All,
+		Mono:           c.Mono,
+		Labels:         c.Label,
+		Limit:          c.Limit,
+		LatestPerMinor: c.LatestPerMinor,
+		Installed:      c.Installed,
+		NotInstalled:   c.NotInstalled,
+	}
+
+	for _, value := range c.Constraints {
+		constraint, err := semver.ParseConstraint(value)
+		if err != nil {
+			return fmt.Errorf("invalid constraint: %w", err)
+		}
+
+		filter.Constraints = append(filter.Constraints, constraint)
+	}
+
+	for _, label := range c.Label {
+		_, exists := semver.Labels[label]
+		if !exists {
+			return fmt.Errorf("invalid label: %s", label)
+		}
+	}
+
+	if len(c.Since) > 0 {
+		since, err := time.ParseInLocation(time.DateOnly, c.Since, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid since date: %w", err)
+		}
+
+		filter.Since = since
+	}
+
+	target := app.Config.Platform
+	if len(c.Platform) > 0 {
+		result, err := platform.ParseEngine(c.Platform)
+		if err != nil {
+			return fmt.Errorf("cannot parse platform: %w", err)
+		}
+
+		target = result
+	}
+
+	if c.Combined {
+		return c.runCombined(ctx, app, filter, target)
+	}
+
+	downloads, err := app.Versions.Available(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("cannot list versions: %w", err)
 	}
 
+	statuses, err := app.Versions.Statuses(semversOf(downloads, c.Mono), target)
+	if err != nil {
+		return fmt.Errorf("cannot determine version statuses: %w", err)
+	}
+
+	t := &output.Table{
+		Columns: append([]output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "available", Title: string(target)},
+		}, statusColumns...),
+	}
+
+	for index, download := range downloads {
+		available := download.HasAsset(target)
+		appendStatusRow(t, statuses[index], download.Relver.Version, download.Relver.Release, available)
+	}
+
+	err = output.Render(output.Format(app.Config.Output), os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render versions: %w", err)
+	}
+
 	return nil
 }
 
+func (c *List) runCombined(ctx context.Context, app *gevm.App, filter versions.Filter, target platform.Platform) error {
+	releases, err := app.Versions.Combined(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("cannot list versions: %w", err)
+	}
+
+	var semvers []semver.Semver
+	for _, release := range releases {
+		semvers = append(semvers, semver.Semver{Relver: release.Relver}, semver.Semver{Relver: release.Relver, Mono: true})
+	}
+
+	statuses, err := app.Versions.Statuses(semvers, target)
+	if err != nil {
+		return fmt.Errorf("cannot determine version statuses: %w", err)
+	}
+
+	t := &output.Table{
+		Columns: append([]output.Column{
+			{Key: "version", Title: "Version"},
+			{Key: "release", Title: "Release"},
+			{Key: "standard", Title: "Standard"},
+			{Key: "mono", Title: "Mono"},
+		}, statusColumns...),
+	}
+
+	for index, release := range releases {
+		standard := release.Standard.HasAsset(target)
+		mono := release.Mono.HasAsset(target)
+
+		a := statuses[index*2]
+		b := statuses[index*2+1]
+		status := versions.Status{
+			Installed:       a.Installed || b.Installed,
+			ExportTemplates: a.ExportTemplates || b.ExportTemplates,
+			Cached:          a.Cached || b.Cached,
+			Newer:           a.Newer || b.Newer,
+		}
+
+		appendStatusRow(t, status, release.Relver.Version, release.Relver.Release, standard, mono)
+	}
+
+	err = output.Render(output.Format(app.Config.Output), os.Stdout, t)
+	if err != nil {
+		return fmt.Errorf("cannot render versions: %w", err)
+	}
+
+	return nil
+}
+
+type Info struct {
+	Version string `arg:"" help:"Godot engine version to view in the format x.x.x.x, x.x.x or x.x"`
+	Release string `short:"r" default:"stable" help:"Release to use (dev1, alpha2, beta3, rc4, stable, etc)"`
+	Mono    bool   `short:"m" help:"View mono assets"`
+}
+
+func (c *Info) Run(ctx context.Context, app *gevm.App) error {
+	release, err := app.Versions.Info(ctx, semver.MaybeRelver(c.Version, c.Release))
+	if err != nil {
+		return fmt.Errorf("cannot view version info: %w", err)
+	}
+
+	download := release.Standard
+	if c.Mono {
+		download = release.Mono
+	}
+
+	t := &output.Table{
+		Columns: []output.Column{
+			{Key: "platform", Title: "Platform"},
+			{Key: "name", Title: "Name"},
+			{Key: "size", Title: "Size", Formatter: formatSize},
+			{Key: "url", Title: "URL"},
+		},
+	}
+
+	for _, platform := range platform.Platforms {
+		asset, ok := download.Assets[platform]
+		if !ok || !asset.IsValid() {
+			continue
+		}
+
+		t.AppendRow(platform, asset.Name, asset.Size, asset.DownloadURL)
+	}
+
+	footprint := download.Assets[app.Config.Platform].Size + download.Assets[platform.ExportTemplates].Size
+
+	format := output.Format(app.Config.Output)
+	if format != output.TABLE {
+		t.Summary = []output.Field{
+			{Key: "version", Value: release.Relver.Version},
+			{Key: "release", Value: release.Relver.Release},
+			{Key: "mono", Value: c.Mono},
+			{Key: "published", Value: release.Published},
+			{Key: "prerelease", Value: release.Prerelease},
+			{Key: "url", Value: release.URL},
+			{Key: "notes", Value: strings.TrimSpace(release.Notes)},
+			{Key: "footprint", Value: footprint},
+		}
+		t.Items = "assets"
+
+		err = output.Render(format, os.Stdout, t)
+		if err != nil {
+			return fmt.Errorf("cannot render assets: %w", err)
+		}
+
+		return nil
+	}
+
+	t.Footer = []any{"Footprint", string(app.Config.Platform), utils.FormatBytes(footprint), ""}
+
+	utils.Printlnf("Version:    %s", release.Relver.Version)
+	utils.Printlnf("Release:    %s", release.Relver.Release)
+	utils.Printlnf("Mono:       %t", c.Mono)
+	utils.Printlnf("Published:  %s", formatDate(release.Published))
+	utils.Printlnf("Prerelease: %t", release.Prerelease)
+	utils.Printlnf("URL:        %s", release.URL)
+
+	if t.IsEmpty() {
+		app.Config.Logger.Info("No assets found")
+	} else {
+		err = output.Render(format, os.Stdout, t)
+		if err != nil {
+			return fmt.Errorf("cannot render assets: %w", err)
+		}
+	}
+
+	notes := strings.TrimSpace(release.Notes)
+	if len(notes) > 0 {
+		utils.Printlnf("")
+		utils.Printlnf("%s", notes)
+	}
+
+	return nil
+}
+
+var statusColumns = []output.Column{
+	{Key: "installed", Title: "Installed?"},
+	{Key: "templates", Title: "Templates?"},
+	{Key: "cached", Title: "Cached?"},
+	{Key: "newer", Title: "Newer?"},
+}
+
+func appendStatusRow(t *output.Table, status versions.Status, row ...any) {
+	row = append(row, status.Installed, status.ExportTemplates, status.Cached, status.Newer)
+	if status.Newer {
+		t.AppendHighlightedRow(row...)
+		return
+	}
+
+	t.AppendRow(row...)
+}
+
+func formatSize(value any) string {
+	return utils.FormatBytes(value.(int64))
+}
+
+func formatDate(value time.Time) string {
+	if value.IsZero() {
+		return "unknown"
+	}
+
+	return value.Local().Format(time.DateTime)
+}
+
+func semversOf(downloads []repository.Download, mono bool) []semver.Semver {
+	var semvers []semver.Semver
+	for _, download := range downloads {
+		semvers = append(semvers, semver.Semver{Relver: download.Relver, Mono: mono})
+	}
+
+	return semvers
+}
+
 type Versions struct {
 	Detailed Detailed `cmd:"" help:"View detailed available versions"`
+	Info     Info     `cmd:"" help:"View release notes and assets of a version"`
 	List     List     `cmd:"" help:"List available versions"`
 }
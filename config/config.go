@@ -2,27 +2,43 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/bashmills/gevm/filesystem"
 	"github.com/bashmills/gevm/internal/logging"
-	"github.com/bashmills/gevm/internal/platform"
 	"github.com/bashmills/gevm/internal/utils"
 	"github.com/bashmills/gevm/logger"
+	"github.com/bashmills/gevm/platform"
 )
 
+const DEFAULT_SOURCE_URL = "https://api.github.com/repos/godotengine/godot-builds"
+
 type Config struct {
-	ExportTemplatesRootDirectory string `json:"export-templates-root-directory"`
-	GodotRootDirectory           string `json:"godot-root-directory"`
-	CacheDirectory               string `json:"cache-directory"`
-	BinDirectory                 string `json:"bin-directory"`
+	ExportTemplatesRootDirectory string   `json:"export-templates-root-directory"`
+	GodotRootDirectory           string   `json:"godot-root-directory"`
+	CacheDirectory               string   `json:"cache-directory"`
+	SharedCacheDirectories       []string `json:"shared-cache-directories"`
+	CopySharedCache              bool     `json:"copy-shared-cache"`
+	LockTimeout                  string   `json:"lock-timeout"`
+	BinDirectory                 string   `json:"bin-directory"`
+	ExportTemplatesPlatforms     string   `json:"export-templates-platforms"`
+	AutoPrune                    bool     `json:"auto-prune"`
+	PruneMaxSize                 string   `json:"prune-max-size"`
+	PruneOlderThan               string   `json:"prune-older-than"`
+	PruneKeepInstalled           bool     `json:"prune-keep-installed"`
+	PruneKeepLatest              int      `json:"prune-keep-latest"`
+	SourceURL                    string   `json:"source-url"`
 
 	ConfigPath string            `json:"-"`
 	Platform   platform.Platform `json:"-"`
 	Logger     logger.Logger     `json:"-"`
 	Silent     bool              `json:"-"`
+	Output     string            `json:"-"`
+
+	Filesystem filesystem.Filesystem `json:"-"`
 }
 
 func (c *Config) Reset() error {
@@ -33,6 +49,13 @@ func (c *Config) Reset() error {
 		return fmt.Errorf("could not create default config: %w", err)
 	}
 
+	config.ConfigPath = c.ConfigPath
+	config.Platform = c.Platform
+	config.Logger = c.Logger
+	config.Silent = c.Silent
+	config.Output = c.Output
+	config.Filesystem = c.Filesystem
+
 	*c = *config
 
 	c.Logger.Trace("Config reset")
@@ -40,10 +63,33 @@ func (c *Config) Reset() error {
 	return nil
 }
 
+func (c *Config) Validate() error {
+	_, err := utils.ParseDuration(c.LockTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid lock-timeout '%s': %w", c.LockTimeout, err)
+	}
+
+	if len(c.PruneMaxSize) > 0 {
+		_, err := utils.ParseBytes(c.PruneMaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid prune-max-size '%s': %w", c.PruneMaxSize, err)
+		}
+	}
+
+	if len(c.PruneOlderThan) > 0 {
+		_, err := utils.ParseDuration(c.PruneOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid prune-older-than '%s': %w", c.PruneOlderThan, err)
+		}
+	}
+
+	return nil
+}
+
 func (c *Config) Save() error {
 	c.Logger.Trace("Attempting to save config: %s", c.ConfigPath)
 
-	err := os.MkdirAll(filepath.Dir(c.ConfigPath), utils.OS_DIRECTORY)
+	err := c.Filesystem.MkdirAll(filepath.Dir(c.ConfigPath), utils.OS_DIRECTORY)
 	if err != nil {
 		return fmt.Errorf("cannot make directory: %w", err)
 	}
@@ -53,13 +99,7 @@ func (c *Config) Save() error {
 		return fmt.Errorf("cannot parse config: %w", err)
 	}
 
-	file, err := os.OpenFile(c.ConfigPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, utils.OS_FILE)
-	if err != nil {
-		return fmt.Errorf("cannot create config: %w", err)
-	}
-	defer file.Close()
-
-	_, err = file.Write(bytes)
+	err = c.Filesystem.WriteFile(c.ConfigPath, bytes, utils.OS_FILE)
 	if err != nil {
 		return fmt.Errorf("cannot write config: %w", err)
 	}
@@ -72,16 +112,10 @@ func (c *Config) Save() error {
 func (c *Config) load() error {
 	c.Logger.Trace("Attempting to load config: %s", c.ConfigPath)
 
-	file, err := os.Open(c.ConfigPath)
-	if os.IsNotExist(err) {
+	bytes, err := c.Filesystem.ReadFile(c.ConfigPath)
+	if errors.Is(err, os.ErrNotExist) {
 		return nil
 	}
-	if err != nil {
-		return fmt.Errorf("cannot open config: %w", err)
-	}
-	defer file.Close()
-
-	bytes, err := io.ReadAll(file)
 	if err != nil {
 		return fmt.Errorf("cannot read config: %w", err)
 	}
@@ -91,6 +125,11 @@ func (c *Config) load() error {
 		return fmt.Errorf("cannot parse config: %w", err)
 	}
 
+	err = c.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid config '%s': %w", c.ConfigPath, err)
+	}
+
 	c.Logger.Trace("Config loaded")
 
 	return nil
@@ -136,12 +175,19 @@ func DefaultConfig() (*Config, error) {
 		ExportTemplatesRootDirectory: defaultExportTemplatesRootDirectory,
 		GodotRootDirectory:           defaultGodotRootDirectory,
 		CacheDirectory:               defaultCacheDirectory,
+		SharedCacheDirectories:       []string{},
 		BinDirectory:                 defaultBinDirectory,
+		PruneKeepInstalled:           true,
+		LockTimeout:                  "10m",
+		SourceURL:                    DEFAULT_SOURCE_URL,
 
 		ConfigPath: configPath,
 		Platform:   platform,
 		Logger:     logger,
 		Silent:     false,
+		Output:     "table",
+
+		Filesystem: filesystem.OS{},
 	}, nil
 }
 
@@ -191,3 +237,19 @@ func OptionSetSilent(silent bool) Option {
 		config.Silent = silent
 	}
 }
+
+func OptionSetOutput(output string) Option {
+	return func(config *Config) {
+		if len(output) > 0 {
+			config.Output = output
+		}
+	}
+}
+
+func OptionSetFilesystem(filesystem filesystem.Filesystem) Option {
+	return func(config *Config) {
+		if filesystem != nil {
+			config.Filesystem = filesystem
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/filesystem"
+	"github.com/bashmills/gevm/internal/logging"
+)
+
+func TestLoadValidates(t *testing.T) {
+	logger, err := logging.New(logging.NOTHING)
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	tests := []struct {
+		Contents string
+		Valid    bool
+	}{
+		{Contents: `{"lock-timeout": "2m"}`, Valid: true},
+		{Contents: `{"lock-timeout": "1w", "prune-older-than": "30d", "prune-max-size": "10GB"}`, Valid: true},
+		{Contents: `{"lock-timeout": "foo"}`, Valid: false},
+		{Contents: `{"lock-timeout": ""}`, Valid: false},
+		{Contents: `{"prune-older-than": "soon"}`, Valid: false},
+		{Contents: `{"prune-max-size": "big"}`, Valid: false},
+	}
+
+	for _, test := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+
+		err := os.WriteFile(configPath, []byte(test.Contents), 0644)
+		if err != nil {
+			t.Fatalf("cannot write config: %s", err)
+		}
+
+		_, err = New(OptionSetConfigPath(configPath), OptionSetLogger(logger))
+		if (err == nil) != test.Valid {
+			t.Errorf("expected valid %t for '%s' but got: %v", test.Valid, test.Contents, err)
+		}
+	}
+}
+
+func TestSaveLoadFilesystem(t *testing.T) {
+	logger, err := logging.New(logging.NOTHING)
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	root := t.TempDir()
+
+	sandbox, err := filesystem.NewSandbox(root)
+	if err != nil {
+		t.Fatalf("cannot create sandbox: %s", err)
+	}
+
+	config, err := New(OptionSetConfigPath("/settings/config.json"), OptionSetLogger(logger), OptionSetFilesystem(sandbox))
+	if err != nil {
+		t.Fatalf("cannot create config: %s", err)
+	}
+
+	config.LockTimeout = "2m"
+
+	err = config.Save()
+	if err != nil {
+		t.Fatalf("cannot save config: %s", err)
+	}
+
+	_, err = os.Stat(filepath.Join(root, "settings", "config.json"))
+	if err != nil {
+		t.Errorf("config not saved inside the sandbox: %s", err)
+	}
+
+	loaded, err := New(OptionSetConfigPath("/settings/config.json"), OptionSetLogger(logger), OptionSetFilesystem(sandbox))
+	if err != nil {
+		t.Fatalf("cannot load config: %s", err)
+	}
+
+	if loaded.LockTimeout != "2m" {
+		t.Errorf("expected lock timeout to be loaded from the sandbox but got: %s", loaded.LockTimeout)
+	}
+}
@@ -0,0 +1,28 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrVersionNotFound = errors.New("version not found")
+var ErrAssetNotFound = errors.New("asset not found")
+var ErrNotInstalled = errors.New("not installed")
+var ErrAlreadyInstalled = errors.New("already installed")
+var ErrNetwork = errors.New("network failure")
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+var ErrSettingNotFound = errors.New("setting not found")
+var ErrLockTimeout = errors.New("lock timeout")
+
+type AssetNotFoundError struct {
+	Platform string
+	Version  string
+}
+
+func (e *AssetNotFoundError) Error() string {
+	return fmt.Sprintf("%s: '%s' for platform: %s", ErrAssetNotFound, e.Version, e.Platform)
+}
+
+func (e *AssetNotFoundError) Is(target error) bool {
+	return target == ErrAssetNotFound
+}
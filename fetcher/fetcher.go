@@ -0,0 +1,20 @@
+package fetcher
+
+import (
+	"context"
+
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+)
+
+type Fetcher interface {
+	FetchAsset(ctx context.Context, platform platform.Platform, semver semver.Semver) (*repository.Asset, error)
+	FetchDownloads(ctx context.Context, mono bool) ([]repository.Download, error)
+}
+
+// DownloadFetcher is optionally implemented by fetchers that can look up a
+// single release without listing every release.
+type DownloadFetcher interface {
+	FetchDownload(ctx context.Context, relver semver.Relver, mono bool) (*repository.Download, error)
+}
@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+)
+
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Seeker
+	io.Closer
+
+	Name() string
+	Stat() (fs.FileInfo, error)
+	Chmod(mode fs.FileMode) error
+}
+
+type Descriptor interface {
+	Fd() uintptr
+}
+
+type Filesystem interface {
+	Open(name string) (File, error)
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	CreateTemp(dir string, pattern string) (File, error)
+	MkdirTemp(dir string, pattern string) (string, error)
+	MkdirAll(path string, perm fs.FileMode) error
+	Remove(name string) error
+	RemoveAll(path string) error
+	Rename(from string, to string) error
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	Readlink(name string) (string, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Chmod(name string, mode fs.FileMode) error
+	Symlink(target string, name string) error
+	Link(target string, name string) error
+	WalkDir(root string, fn fs.WalkDirFunc) error
+}
+
+func DescriptorOf(file File) (uintptr, bool) {
+	for {
+		descriptor, ok := file.(Descriptor)
+		if ok {
+			return descriptor.Fd(), true
+		}
+
+		wrapper, ok := file.(interface{ Unwrap() File })
+		if !ok {
+			return 0, false
+		}
+
+		file = wrapper.Unwrap()
+	}
+}
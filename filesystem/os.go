@@ -0,0 +1,89 @@
+package filesystem
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type OS struct{}
+
+func (OS) Open(name string) (File, error) {
+	return wrap(os.Open(name))
+}
+
+func (OS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	return wrap(os.OpenFile(name, flag, perm))
+}
+
+func (OS) CreateTemp(dir string, pattern string) (File, error) {
+	return wrap(os.CreateTemp(dir, pattern))
+}
+
+func (OS) MkdirTemp(dir string, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+func (OS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (OS) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (OS) Rename(from string, to string) error {
+	return os.Rename(from, to)
+}
+
+func (OS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OS) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (OS) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+func (OS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (OS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OS) Chmod(name string, mode fs.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+func (OS) Symlink(target string, name string) error {
+	return os.Symlink(target, name)
+}
+
+func (OS) Link(target string, name string) error {
+	return os.Link(target, name)
+}
+
+func (OS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
+func wrap(file *os.File, err error) (File, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
@@ -0,0 +1,172 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+type Sandbox struct {
+	Root       string
+	Filesystem Filesystem
+}
+
+type sandboxFile struct {
+	File
+	name string
+}
+
+func (f *sandboxFile) Name() string {
+	return f.name
+}
+
+func (f *sandboxFile) Unwrap() File {
+	return f.File
+}
+
+func (s *Sandbox) Open(name string) (File, error) {
+	file, err := s.Filesystem.Open(s.resolve(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sandboxFile{File: file, name: name}, nil
+}
+
+func (s *Sandbox) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	file, err := s.Filesystem.OpenFile(s.resolve(name), flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sandboxFile{File: file, name: name}, nil
+}
+
+func (s *Sandbox) CreateTemp(dir string, pattern string) (File, error) {
+	file, err := s.Filesystem.CreateTemp(s.resolve(dir), pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sandboxFile{File: file, name: s.unresolve(file.Name())}, nil
+}
+
+func (s *Sandbox) MkdirTemp(dir string, pattern string) (string, error) {
+	path, err := s.Filesystem.MkdirTemp(s.resolve(dir), pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return s.unresolve(path), nil
+}
+
+func (s *Sandbox) MkdirAll(path string, perm fs.FileMode) error {
+	return s.Filesystem.MkdirAll(s.resolve(path), perm)
+}
+
+func (s *Sandbox) Remove(name string) error {
+	return s.Filesystem.Remove(s.resolve(name))
+}
+
+func (s *Sandbox) RemoveAll(path string) error {
+	return s.Filesystem.RemoveAll(s.resolve(path))
+}
+
+func (s *Sandbox) Rename(from string, to string) error {
+	return s.Filesystem.Rename(s.resolve(from), s.resolve(to))
+}
+
+func (s *Sandbox) Stat(name string) (fs.FileInfo, error) {
+	return s.Filesystem.Stat(s.resolve(name))
+}
+
+func (s *Sandbox) Lstat(name string) (fs.FileInfo, error) {
+	return s.Filesystem.Lstat(s.resolve(name))
+}
+
+func (s *Sandbox) Readlink(name string) (string, error) {
+	target, err := s.Filesystem.Readlink(s.resolve(name))
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.IsAbs(target) {
+		return s.unresolve(target), nil
+	}
+
+	return target, nil
+}
+
+func (s *Sandbox) ReadDir(name string) ([]fs.DirEntry, error) {
+	return s.Filesystem.ReadDir(s.resolve(name))
+}
+
+func (s *Sandbox) ReadFile(name string) ([]byte, error) {
+	return s.Filesystem.ReadFile(s.resolve(name))
+}
+
+func (s *Sandbox) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return s.Filesystem.WriteFile(s.resolve(name), data, perm)
+}
+
+func (s *Sandbox) Chmod(name string, mode fs.FileMode) error {
+	return s.Filesystem.Chmod(s.resolve(name), mode)
+}
+
+func (s *Sandbox) Symlink(target string, name string) error {
+	if filepath.IsAbs(target) {
+		return s.Filesystem.Symlink(s.resolve(target), s.resolve(name))
+	}
+
+	resolved := s.resolve(filepath.Join(filepath.Dir(s.clean(name)), target))
+
+	relative, err := filepath.Rel(filepath.Dir(s.resolve(name)), resolved)
+	if err != nil {
+		return fmt.Errorf("cannot determine relative target: %w", err)
+	}
+
+	return s.Filesystem.Symlink(relative, s.resolve(name))
+}
+
+func (s *Sandbox) Link(target string, name string) error {
+	return s.Filesystem.Link(s.resolve(target), s.resolve(name))
+}
+
+func (s *Sandbox) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return s.Filesystem.WalkDir(s.resolve(root), func(path string, d fs.DirEntry, err error) error {
+		return fn(s.unresolve(path), d, err)
+	})
+}
+
+// clean roots the path at the sandbox so any ".." elements stop at its root
+// rather than climbing out of it.
+func (s *Sandbox) clean(path string) string {
+	path = strings.TrimPrefix(path, filepath.VolumeName(path))
+	return filepath.Clean(string(filepath.Separator) + path)
+}
+
+func (s *Sandbox) resolve(path string) string {
+	return filepath.Join(s.Root, s.clean(path))
+}
+
+func (s *Sandbox) unresolve(path string) string {
+	relative, err := filepath.Rel(s.Root, path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(string(filepath.Separator), relative)
+}
+
+func NewSandbox(root string) (*Sandbox, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine absolute root: %w", err)
+	}
+
+	return &Sandbox{
+		Root:       root,
+		Filesystem: OS{},
+	}, nil
+}
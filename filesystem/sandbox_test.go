@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSandboxResolve(t *testing.T) {
+	sandbox, err := NewSandbox(t.TempDir())
+	if err != nil {
+		t.Fatalf("cannot create sandbox: %s", err)
+	}
+
+	tests := []struct {
+		Path     string
+		Expected string
+	}{
+		{Path: "/godot/4.3-stable", Expected: "godot/4.3-stable"},
+		{Path: "godot", Expected: "godot"},
+		{Path: "/../../etc/passwd", Expected: "etc/passwd"},
+		{Path: "../etc/passwd", Expected: "etc/passwd"},
+		{Path: "/godot/../../cache", Expected: "cache"},
+		{Path: "/", Expected: ""},
+	}
+
+	for _, test := range tests {
+		expected := filepath.Join(sandbox.Root, filepath.FromSlash(test.Expected))
+		resolved := sandbox.resolve(filepath.FromSlash(test.Path))
+		if resolved != expected {
+			t.Errorf("expected '%s' to resolve to '%s' but got '%s'", test.Path, expected, resolved)
+		}
+	}
+}
+
+func TestSandboxSymlink(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+
+	sandbox, err := NewSandbox(root)
+	if err != nil {
+		t.Fatalf("cannot create sandbox: %s", err)
+	}
+
+	err = sandbox.MkdirAll("/lib", 0755)
+	if err != nil {
+		t.Fatalf("cannot make directory: %s", err)
+	}
+
+	err = os.WriteFile(filepath.Join(parent, "secret"), []byte("secret"), 0644)
+	if err != nil {
+		t.Fatalf("cannot write secret: %s", err)
+	}
+
+	err = sandbox.WriteFile("/secret", []byte("sandboxed"), 0644)
+	if err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+
+	for _, target := range []string{"../../secret", "../secret", "/secret"} {
+		err = sandbox.RemoveAll("/lib/link")
+		if err != nil {
+			t.Fatalf("cannot remove link: %s", err)
+		}
+
+		err = sandbox.Symlink(filepath.FromSlash(target), "/lib/link")
+		if err != nil {
+			t.Fatalf("cannot create symlink: %s", err)
+		}
+
+		bytes, err := sandbox.ReadFile("/lib/link")
+		if err != nil {
+			t.Fatalf("cannot read through symlink '%s': %s", target, err)
+		}
+
+		if string(bytes) != "sandboxed" {
+			t.Errorf("symlink '%s' escaped the sandbox", target)
+		}
+	}
+}
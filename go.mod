@@ -7,6 +7,9 @@ require (
 	github.com/jedib0t/go-pretty/v6 v6.5.9
 	github.com/jxeng/shortcut v1.0.2
 	github.com/schollz/progressbar/v3 v3.14.4
+	github.com/ulikunitz/xz v0.5.12
+	golang.org/x/sys v0.20.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,6 +17,5 @@ require (
 	github.com/mattn/go-runewidth v0.0.15 // indirect
 	github.com/mitchellh/colorstring v0.0.0-20190213212951-d06e56a500db // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/term v0.20.0 // indirect
 )
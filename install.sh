@@ -11,8 +11,12 @@ if [[ $GEVM_OS == "Darwin" ]]; then
 elif [[ $GEVM_OS == "Linux" ]]; then
     if [[ $GEVM_ARCH == "x86_64" ]]; then
         GEVM_FILENAME="gevm-linux-amd64.zip"
-    elif [[ $GEVM_ARCH == "arm64" ]]; then
+    elif [[ $GEVM_ARCH == "arm64" || $GEVM_ARCH == "aarch64" ]]; then
         GEVM_FILENAME="gevm-linux-arm64.zip"
+    elif [[ $GEVM_ARCH == "i386" || $GEVM_ARCH == "i686" ]]; then
+        GEVM_FILENAME="gevm-linux-386.zip"
+    elif [[ $GEVM_ARCH == armv6* || $GEVM_ARCH == armv7* ]]; then
+        GEVM_FILENAME="gevm-linux-arm.zip"
     fi
 fi
 
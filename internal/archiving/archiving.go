@@ -1,67 +1,486 @@
 package archiving
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/filesystem"
 	"github.com/bashmills/gevm/internal/utils"
-	"github.com/bashmills/gevm/logger"
+	"github.com/ulikunitz/xz"
 )
 
-func Unzip(logger logger.Logger, from string, to string) error {
-	reader, err := zip.OpenReader(from)
+type Format string
+
+const (
+	UNKNOWN Format = ""
+	ZIP     Format = "zip"
+	TAR     Format = "tar"
+	GZIP    Format = "tar.gz"
+	XZ      Format = "tar.xz"
+)
+
+const HEADER_SIZE = 512
+const TAR_MAGIC_OFFSET = 257
+const MAX_SYMLINK_HOPS = 255
+
+var ZipMagic = []byte("PK\x03\x04")
+var GzipMagic = []byte{0x1f, 0x8b}
+var XzMagic = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+var TarMagic = []byte("ustar")
+
+var ErrIllegalPath = errors.New("illegal path")
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+type Filter func(name string) bool
+
+func Extract(ctx context.Context, config *config.Config, from string, to string, filter Filter) error {
+	file, err := config.Filesystem.Open(from)
 	if err != nil {
 		return fmt.Errorf("could not open source file: %w", err)
 	}
-	defer reader.Close()
+	defer file.Close()
 
-	logger.Info("Unzipping '%s'", filepath.Base(from))
-
-	err = unzip(reader, to)
+	format, err := detect(file)
 	if err != nil {
-		return fmt.Errorf("cannot unzip file: %w", err)
+		return fmt.Errorf("could not detect format: %w", err)
+	}
+
+	config.Logger.Info("Extracting '%s'", filepath.Base(from))
+	config.Logger.Trace("Archive format detected: %s", format)
+
+	switch format {
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Base(from))
+	case ZIP:
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("could not stat source file: %w", err)
+		}
+
+		reader, err := zip.NewReader(file, info.Size())
+		if err != nil {
+			return fmt.Errorf("could not open zip: %w", err)
+		}
+
+		err = unzip(ctx, config.Filesystem, reader, to, filter)
+		if err != nil {
+			return fmt.Errorf("cannot unzip file: %w", err)
+		}
+	case TAR:
+		err = untar(ctx, config.Filesystem, tar.NewReader(file), to, filter)
+		if err != nil {
+			return fmt.Errorf("cannot untar file: %w", err)
+		}
+	case GZIP:
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("could not open gzip: %w", err)
+		}
+		defer reader.Close()
+
+		err = untar(ctx, config.Filesystem, tar.NewReader(reader), to, filter)
+		if err != nil {
+			return fmt.Errorf("cannot untar file: %w", err)
+		}
+	case XZ:
+		reader, err := xz.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("could not open xz: %w", err)
+		}
+
+		err = untar(ctx, config.Filesystem, tar.NewReader(reader), to, filter)
+		if err != nil {
+			return fmt.Errorf("cannot untar file: %w", err)
+		}
 	}
 
 	return nil
 }
 
-func unzip(reader *zip.ReadCloser, to string) error {
+func detect(file filesystem.File) (Format, error) {
+	header := make([]byte, HEADER_SIZE)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return UNKNOWN, fmt.Errorf("could not read header: %w", err)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return UNKNOWN, fmt.Errorf("could not rewind file: %w", err)
+	}
+
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, ZipMagic):
+		return ZIP, nil
+	case bytes.HasPrefix(header, GzipMagic):
+		return GZIP, nil
+	case bytes.HasPrefix(header, XzMagic):
+		return XZ, nil
+	case len(header) >= TAR_MAGIC_OFFSET+len(TarMagic) && bytes.Equal(header[TAR_MAGIC_OFFSET:TAR_MAGIC_OFFSET+len(TarMagic)], TarMagic):
+		return TAR, nil
+	}
+
+	return UNKNOWN, nil
+}
+
+func unzip(ctx context.Context, filesystem filesystem.Filesystem, reader *zip.Reader, to string, filter Filter) error {
 	for _, file := range reader.File {
-		path := filepath.Join(to, file.Name)
-		if file.FileInfo().IsDir() {
-			err := os.MkdirAll(path, utils.OS_DIRECTORY)
-			if err != nil {
-				return fmt.Errorf("could not make directory: %w", err)
-			}
+		if ctx.Err() != nil {
+			return fmt.Errorf("extraction interrupted: %w", ctx.Err())
+		}
 
+		if filter != nil && !file.FileInfo().IsDir() && !filter(file.Name) {
 			continue
 		}
 
-		err := os.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+		err := unzipFile(ctx, filesystem, file, to)
 		if err != nil {
-			return fmt.Errorf("could not make directory: %w", err)
+			return fmt.Errorf("could not unzip '%s': %w", file.Name, err)
 		}
+	}
+
+	return nil
+}
+
+func unzipFile(ctx context.Context, filesystem filesystem.Filesystem, file *zip.File, to string) error {
+	path, err := ResolvePath(to, file.Name)
+	if err != nil {
+		return fmt.Errorf("could not resolve path: %w", err)
+	}
+
+	mode := file.Mode()
+	if mode.IsDir() {
+		return writeDirectory(filesystem, to, path)
+	}
+
+	path, err = resolveParent(filesystem, to, path)
+	if err != nil {
+		return fmt.Errorf("could not resolve parent: %w", err)
+	}
 
-		dst, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, file.Mode())
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("could not open zip file: %w", err)
+	}
+	defer src.Close()
+
+	if mode&fs.ModeSymlink != 0 {
+		bytes, err := io.ReadAll(src)
 		if err != nil {
-			return fmt.Errorf("could not create destination file: %w", err)
+			return fmt.Errorf("could not read symlink target: %w", err)
+		}
+
+		return writeSymlink(filesystem, to, path, string(bytes))
+	}
+
+	return writeFile(ctx, filesystem, path, src, mode)
+}
+
+func untar(ctx context.Context, filesystem filesystem.Filesystem, reader *tar.Reader, to string, filter Filter) error {
+	for {
+		if ctx.Err() != nil {
+			return fmt.Errorf("extraction interrupted: %w", ctx.Err())
 		}
-		defer dst.Close()
 
-		src, err := file.Open()
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			return fmt.Errorf("could not open zip file: %w", err)
+			return fmt.Errorf("could not read tar header: %w", err)
 		}
-		defer src.Close()
 
-		_, err = io.Copy(dst, src)
+		if filter != nil && header.Typeflag != tar.TypeDir && !filter(header.Name) {
+			continue
+		}
+
+		err = untarFile(ctx, filesystem, reader, header, to)
 		if err != nil {
-			return fmt.Errorf("could not copy file: %w", err)
+			return fmt.Errorf("could not untar '%s': %w", header.Name, err)
 		}
 	}
 
 	return nil
 }
+
+func untarFile(ctx context.Context, filesystem filesystem.Filesystem, reader *tar.Reader, header *tar.Header, to string) error {
+	path, err := ResolvePath(to, header.Name)
+	if err != nil {
+		return fmt.Errorf("could not resolve path: %w", err)
+	}
+
+	if header.Typeflag == tar.TypeDir {
+		return writeDirectory(filesystem, to, path)
+	}
+
+	path, err = resolveParent(filesystem, to, path)
+	if err != nil {
+		return fmt.Errorf("could not resolve parent: %w", err)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeReg:
+		return writeFile(ctx, filesystem, path, reader, header.FileInfo().Mode())
+	case tar.TypeSymlink:
+		return writeSymlink(filesystem, to, path, header.Linkname)
+	case tar.TypeLink:
+		return writeLink(filesystem, to, path, header.Linkname)
+	}
+
+	return nil
+}
+
+func writeDirectory(filesystem filesystem.Filesystem, root string, path string) error {
+	relative, err := filepath.Rel(root, path)
+	if err != nil {
+		return fmt.Errorf("directory outside root: %w: %s", ErrIllegalPath, path)
+	}
+
+	path, err = resolveLinks(filesystem, root, relative)
+	if err != nil {
+		return fmt.Errorf("could not resolve directory: %w", err)
+	}
+
+	err = filesystem.MkdirAll(path, utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("could not make directory: %w", err)
+	}
+
+	return nil
+}
+
+func writeFile(ctx context.Context, filesystem filesystem.Filesystem, path string, src io.Reader, mode fs.FileMode) error {
+	err := filesystem.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("could not make directory: %w", err)
+	}
+
+	err = removeSymlink(filesystem, path)
+	if err != nil {
+		return fmt.Errorf("could not remove existing symlink: %w", err)
+	}
+
+	perm := fs.FileMode(utils.OS_FILE)
+	if mode&(utils.OS_USER_X|utils.OS_GROUP_X|utils.OS_OTHER_X) != 0 {
+		perm = utils.OS_EXECUTABLE
+	}
+
+	dst, err := filesystem.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return fmt.Errorf("could not create destination file: %w", err)
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, utils.NewContextReader(ctx, src))
+	if ctx.Err() != nil {
+		return fmt.Errorf("extraction interrupted: %w", ctx.Err())
+	}
+	if err != nil {
+		return fmt.Errorf("could not copy file: %w", err)
+	}
+
+	err = dst.Chmod(perm)
+	if err != nil {
+		return fmt.Errorf("could not set file permissions: %w", err)
+	}
+
+	return nil
+}
+
+func writeSymlink(filesystem filesystem.Filesystem, root string, path string, target string) error {
+	if filepath.IsAbs(target) {
+		return fmt.Errorf("absolute symlink target: %w: %s", ErrIllegalPath, target)
+	}
+
+	resolved := filepath.Join(filepath.Dir(path), filepath.FromSlash(target))
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("symlink target outside root: %w: %s", ErrIllegalPath, target)
+	}
+
+	parent, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("symlink outside root: %w: %s", ErrIllegalPath, path)
+	}
+
+	_, err = resolveLinks(filesystem, root, parent+string(filepath.Separator)+filepath.FromSlash(target))
+	if err != nil {
+		return fmt.Errorf("symlink target outside root: %w", err)
+	}
+
+	err = filesystem.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("could not make directory: %w", err)
+	}
+
+	err = filesystem.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("could not remove existing file: %w", err)
+	}
+
+	err = filesystem.Symlink(target, path)
+	if err != nil {
+		return fmt.Errorf("could not create symlink: %w", err)
+	}
+
+	return nil
+}
+
+func writeLink(filesystem filesystem.Filesystem, root string, path string, target string) error {
+	resolved, err := ResolvePath(root, target)
+	if err != nil {
+		return fmt.Errorf("could not resolve link target: %w", err)
+	}
+
+	resolved, err = resolveParent(filesystem, root, resolved)
+	if err != nil {
+		return fmt.Errorf("could not resolve link target: %w", err)
+	}
+
+	err = filesystem.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("could not make directory: %w", err)
+	}
+
+	err = filesystem.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("could not remove existing file: %w", err)
+	}
+
+	err = filesystem.Link(resolved, path)
+	if err != nil {
+		return fmt.Errorf("could not create link: %w", err)
+	}
+
+	return nil
+}
+
+func ResolvePath(root string, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\") {
+		return "", fmt.Errorf("absolute path: %w: %s", ErrIllegalPath, name)
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("path outside root: %w: %s", ErrIllegalPath, name)
+	}
+
+	return path, nil
+}
+
+// resolveParent resolves any symlinks in the directories leading up to path
+// on disk and keeps the final element as is so it is never followed.
+func resolveParent(filesystem filesystem.Filesystem, root string, path string) (string, error) {
+	relative, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return "", fmt.Errorf("path outside root: %w: %s", ErrIllegalPath, path)
+	}
+
+	parent, err := resolveLinks(filesystem, root, relative)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(parent, filepath.Base(path)), nil
+}
+
+// resolveLinks walks the uncleaned relative path one element at a time below
+// root following any symlinks already on disk and fails as soon as the walk
+// would leave root. Elements that do not exist yet are taken literally.
+func resolveLinks(filesystem filesystem.Filesystem, root string, relative string) (string, error) {
+	hops := 0
+	resolved := ""
+	remaining := strings.Split(relative, string(filepath.Separator))
+	for len(remaining) > 0 {
+		element := remaining[0]
+		remaining = remaining[1:]
+
+		switch element {
+		case "", ".":
+			continue
+		case "..":
+			if resolved == "" {
+				return "", fmt.Errorf("path outside root: %w: %s", ErrIllegalPath, relative)
+			}
+
+			resolved = filepath.Dir(resolved)
+			if resolved == "." {
+				resolved = ""
+			}
+
+			continue
+		}
+
+		next := filepath.Join(resolved, element)
+		info, err := filesystem.Lstat(filepath.Join(root, next))
+		if errors.Is(err, os.ErrNotExist) {
+			resolved = next
+			continue
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("could not stat '%s': %w", next, err)
+		}
+
+		if info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		hops++
+		if hops > MAX_SYMLINK_HOPS {
+			return "", fmt.Errorf("too many symlinks: %w: %s", ErrIllegalPath, relative)
+		}
+
+		target, err := filesystem.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", fmt.Errorf("could not read symlink '%s': %w", next, err)
+		}
+
+		if filepath.IsAbs(target) {
+			return "", fmt.Errorf("absolute symlink target: %w: %s", ErrIllegalPath, target)
+		}
+
+		remaining = append(strings.Split(filepath.FromSlash(target), string(filepath.Separator)), remaining...)
+	}
+
+	return filepath.Join(root, resolved), nil
+}
+
+func removeSymlink(filesystem filesystem.Filesystem, path string) error {
+	info, err := filesystem.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+
+	return filesystem.Remove(path)
+}
+
+func isWithin(root string, path string) bool {
+	relative, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,373 @@
+package archiving
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bashmills/gevm/filesystem"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/ulikunitz/xz"
+)
+
+type entry struct {
+	Name     string
+	Typeflag byte
+	Linkname string
+	Body     string
+}
+
+func newTar(t *testing.T, entries []entry) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, entry := range entries {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     entry.Name,
+			Typeflag: entry.Typeflag,
+			Linkname: entry.Linkname,
+			Mode:     0644,
+			Size:     int64(len(entry.Body)),
+		})
+		if err != nil {
+			t.Fatalf("cannot write header: %s", err)
+		}
+
+		_, err = writer.Write([]byte(entry.Body))
+		if err != nil {
+			t.Fatalf("cannot write body: %s", err)
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close tar: %s", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func writeTar(t *testing.T, entries []entry) *tar.Reader {
+	t.Helper()
+
+	return tar.NewReader(bytes.NewReader(newTar(t, entries)))
+}
+
+type zipEntry struct {
+	Name string
+	Mode fs.FileMode
+	Body string
+}
+
+func writeZip(t *testing.T, entries []zipEntry) *zip.Reader {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.Name}
+		header.SetMode(entry.Mode)
+
+		file, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("cannot create entry: %s", err)
+		}
+
+		_, err = file.Write([]byte(entry.Body))
+		if err != nil {
+			t.Fatalf("cannot write entry: %s", err)
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close zip: %s", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("cannot read zip: %s", err)
+	}
+
+	return reader
+}
+
+func TestUnzipContainment(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Entries []zipEntry
+	}{
+		{Name: "parent", Entries: []zipEntry{{Name: "../evil", Mode: 0644, Body: "evil"}}},
+		{Name: "nested parent", Entries: []zipEntry{{Name: "bin/../../evil", Mode: 0644, Body: "evil"}}},
+		{Name: "absolute symlink", Entries: []zipEntry{{Name: "link", Mode: fs.ModeSymlink | 0777, Body: "/"}, {Name: "link/evil", Mode: 0644, Body: "evil"}}},
+		{Name: "escaping symlink", Entries: []zipEntry{{Name: "link", Mode: fs.ModeSymlink | 0777, Body: ".."}, {Name: "link/evil", Mode: 0644, Body: "evil"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			parent := t.TempDir()
+			root := filepath.Join(parent, "root")
+			err := os.Mkdir(root, 0755)
+			if err != nil {
+				t.Fatalf("cannot make root: %s", err)
+			}
+
+			err = unzip(context.Background(), filesystem.OS{}, writeZip(t, test.Entries), root, nil)
+			if !errors.Is(err, ErrIllegalPath) {
+				t.Errorf("expected illegal path error but got: %v", err)
+			}
+
+			_, err = os.Lstat(filepath.Join(parent, "evil"))
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("file escaped the root")
+			}
+		})
+	}
+}
+
+func TestUnzipModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not supported on windows")
+	}
+
+	root := t.TempDir()
+	entries := []zipEntry{
+		{Name: "Godot.app/Contents/MacOS/Godot", Mode: 0755, Body: "godot"},
+		{Name: "Godot.app/Contents/Info.plist", Mode: 0644, Body: "plist"},
+		{Name: "Godot.app/Contents/MacOS/Current", Mode: fs.ModeSymlink | 0777, Body: "Godot"},
+	}
+
+	err := unzip(context.Background(), filesystem.OS{}, writeZip(t, entries), root, nil)
+	if err != nil {
+		t.Fatalf("cannot unzip: %s", err)
+	}
+
+	for name, expected := range map[string]fs.FileMode{
+		"Godot.app/Contents/MacOS/Godot": 0755,
+		"Godot.app/Contents/Info.plist":  0644,
+	} {
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("cannot stat '%s': %s", name, err)
+			continue
+		}
+
+		if info.Mode().Perm() != expected {
+			t.Errorf("expected mode %s for '%s' but got %s", expected, name, info.Mode().Perm())
+		}
+	}
+
+	target, err := os.Readlink(filepath.Join(root, "Godot.app", "Contents", "MacOS", "Current"))
+	if err != nil || target != "Godot" {
+		t.Errorf("expected symlink to be preserved but got: %s %v", target, err)
+	}
+}
+
+func TestUntarContainment(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Entries []entry
+		Escaped string
+	}{
+		{
+			Name: "symlink chain",
+			Entries: []entry{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "b/evil", Typeflag: tar.TypeReg, Body: "evil"},
+			},
+			Escaped: "evil",
+		},
+		{
+			Name: "symlink through symlink",
+			Entries: []entry{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "d", Typeflag: tar.TypeSymlink, Linkname: "a/../evil"},
+				{Name: "d", Typeflag: tar.TypeReg, Body: "evil"},
+			},
+			Escaped: "evil",
+		},
+		{
+			Name: "hardlink through symlink",
+			Entries: []entry{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "c", Typeflag: tar.TypeLink, Linkname: "b/secret"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			parent := t.TempDir()
+			root := filepath.Join(parent, "root")
+			err := os.Mkdir(root, 0755)
+			if err != nil {
+				t.Fatalf("cannot make root: %s", err)
+			}
+
+			err = os.WriteFile(filepath.Join(parent, "secret"), []byte("secret"), 0644)
+			if err != nil {
+				t.Fatalf("cannot write secret: %s", err)
+			}
+
+			err = untar(context.Background(), filesystem.OS{}, writeTar(t, test.Entries), root, nil)
+			if !errors.Is(err, ErrIllegalPath) {
+				t.Errorf("expected illegal path error but got: %v", err)
+			}
+
+			if len(test.Escaped) > 0 {
+				_, err = os.Lstat(filepath.Join(parent, test.Escaped))
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("file escaped the root: %s", test.Escaped)
+				}
+			}
+		})
+	}
+}
+
+func TestUntarSymlinks(t *testing.T) {
+	root := t.TempDir()
+	entries := []entry{
+		{Name: "lib/libgodot.so.1", Typeflag: tar.TypeReg, Body: "library"},
+		{Name: "lib/libgodot.so", Typeflag: tar.TypeSymlink, Linkname: "libgodot.so.1"},
+		{Name: "current", Typeflag: tar.TypeSymlink, Linkname: "lib"},
+		{Name: "current/notes.txt", Typeflag: tar.TypeReg, Body: "notes"},
+		{Name: "copy", Typeflag: tar.TypeLink, Linkname: "lib/libgodot.so.1"},
+	}
+
+	err := untar(context.Background(), filesystem.OS{}, writeTar(t, entries), root, nil)
+	if err != nil {
+		t.Fatalf("cannot untar: %s", err)
+	}
+
+	for path, expected := range map[string]string{
+		"lib/libgodot.so": "library",
+		"lib/notes.txt":   "notes",
+		"copy":            "library",
+	} {
+		bytes, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Errorf("cannot read '%s': %s", path, err)
+			continue
+		}
+
+		if string(bytes) != expected {
+			t.Errorf("expected '%s' in '%s' but got '%s'", expected, path, bytes)
+		}
+	}
+}
+
+type cancellingReader struct {
+	cancel context.CancelFunc
+}
+
+func (r *cancellingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return len(p), nil
+}
+
+func TestWriteFileInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "large")
+
+	err := writeFile(ctx, filesystem.OS{}, path, &cancellingReader{cancel: cancel}, 0644)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation but got: %v", err)
+	}
+}
+
+func compress(t *testing.T, format Format, data []byte) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	var err error
+
+	switch format {
+	case GZIP:
+		writer = gzip.NewWriter(&buffer)
+	case XZ:
+		writer, err = xz.NewWriter(&buffer)
+		if err != nil {
+			t.Fatalf("cannot create xz writer: %s", err)
+		}
+	default:
+		return data
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		t.Fatalf("cannot compress: %s", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close compressor: %s", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestExtractFormats(t *testing.T) {
+	entries := []entry{
+		{Name: "templates/version.txt", Typeflag: tar.TypeReg, Body: "4.3.stable"},
+		{Name: "templates/linux_release.x86_64", Typeflag: tar.TypeReg, Body: "template"},
+	}
+
+	for _, format := range []Format{TAR, GZIP, XZ} {
+		t.Run(string(format), func(t *testing.T) {
+			root := t.TempDir()
+			config := fixtures.NewConfig(t, root)
+			from := filepath.Join(root, "archive."+string(format))
+			to := filepath.Join(root, "extracted")
+
+			err := os.WriteFile(from, compress(t, format, newTar(t, entries)), 0644)
+			if err != nil {
+				t.Fatalf("cannot write archive: %s", err)
+			}
+
+			err = Extract(context.Background(), config, from, to, func(name string) bool {
+				return name != "templates/linux_release.x86_64"
+			})
+			if err != nil {
+				t.Fatalf("cannot extract: %s", err)
+			}
+
+			bytes, err := os.ReadFile(filepath.Join(to, "templates", "version.txt"))
+			if err != nil || string(bytes) != "4.3.stable" {
+				t.Errorf("expected extracted file but got: %s %v", bytes, err)
+			}
+
+			_, err = os.Stat(filepath.Join(to, "templates", "linux_release.x86_64"))
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected filtered file to be skipped")
+			}
+		})
+	}
+
+	root := t.TempDir()
+	from := filepath.Join(root, "archive.rar")
+
+	err := os.WriteFile(from, []byte("Rar!"), 0644)
+	if err != nil {
+		t.Fatalf("cannot write archive: %s", err)
+	}
+
+	err = Extract(context.Background(), fixtures.NewConfig(t, root), from, filepath.Join(root, "extracted"), nil)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected unsupported format but got: %v", err)
+	}
+}
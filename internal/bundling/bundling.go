@@ -0,0 +1,314 @@
+package bundling
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/filesystem"
+	"github.com/bashmills/gevm/internal/archiving"
+	"github.com/bashmills/gevm/internal/utils"
+)
+
+const MANIFEST_NAME = "manifest.json"
+const MANIFEST_VERSION = 1
+const TEMP_PATTERN = ".bundle-*"
+
+var ErrChecksumMismatch = errs.ErrChecksumMismatch
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+type Entry struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"sha256"`
+}
+
+type Manifest struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+func Write(ctx context.Context, filesystem filesystem.Filesystem, bundlePath string, root string, paths []string) error {
+	manifest := Manifest{
+		Version: MANIFEST_VERSION,
+	}
+
+	for _, path := range paths {
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("cannot determine relative path: %w", err)
+		}
+
+		info, err := filesystem.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot stat file: %w", err)
+		}
+
+		checksum, err := utils.ChecksumFile(filesystem, path)
+		if err != nil {
+			return fmt.Errorf("cannot checksum file: %w", err)
+		}
+
+		manifest.Entries = append(manifest.Entries, Entry{
+			Path:     filepath.ToSlash(relative),
+			Size:     info.Size(),
+			Checksum: checksum,
+		})
+	}
+
+	bytes, err := json.MarshalIndent(manifest, "", "	")
+	if err != nil {
+		return fmt.Errorf("cannot encode manifest: %w", err)
+	}
+
+	err = filesystem.MkdirAll(filepath.Dir(bundlePath), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	file, err := filesystem.CreateTemp(filepath.Dir(bundlePath), TEMP_PATTERN)
+	if err != nil {
+		return fmt.Errorf("cannot create bundle: %w", err)
+	}
+	defer filesystem.Remove(file.Name())
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+
+	err = writer.WriteHeader(&tar.Header{
+		Name:    MANIFEST_NAME,
+		Mode:    utils.OS_FILE,
+		Size:    int64(len(bytes)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write manifest header: %w", err)
+	}
+
+	_, err = writer.Write(bytes)
+	if err != nil {
+		return fmt.Errorf("cannot write manifest: %w", err)
+	}
+
+	for _, entry := range manifest.Entries {
+		if ctx.Err() != nil {
+			return fmt.Errorf("bundle interrupted: %w", ctx.Err())
+		}
+
+		err := writeEntry(ctx, filesystem, writer, root, entry)
+		if err != nil {
+			return fmt.Errorf("cannot write '%s': %w", entry.Path, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("cannot finish bundle: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close bundle: %w", err)
+	}
+
+	err = filesystem.Chmod(file.Name(), utils.OS_FILE)
+	if err != nil {
+		return fmt.Errorf("cannot set file permissions: %w", err)
+	}
+
+	err = filesystem.Rename(file.Name(), bundlePath)
+	if err != nil {
+		return fmt.Errorf("cannot move bundle: %w", err)
+	}
+
+	return nil
+}
+
+func Read(ctx context.Context, filesystem filesystem.Filesystem, bundlePath string, root string) ([]Entry, error) {
+	file, err := filesystem.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open bundle: %w", err)
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(file)
+
+	header, err := reader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest header: %w", err)
+	}
+
+	if header.Name != MANIFEST_NAME {
+		return nil, fmt.Errorf("%w: manifest missing", ErrInvalidBundle)
+	}
+
+	var manifest Manifest
+	err = json.NewDecoder(reader).Decode(&manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse manifest: %w", err)
+	}
+
+	if manifest.Version != MANIFEST_VERSION {
+		return nil, fmt.Errorf("%w: unsupported manifest version: %d", ErrInvalidBundle, manifest.Version)
+	}
+
+	entries := map[string]Entry{}
+	for _, entry := range manifest.Entries {
+		entries[entry.Path] = entry
+	}
+
+	err = filesystem.MkdirAll(root, utils.OS_DIRECTORY)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	staging, err := filesystem.MkdirTemp(root, TEMP_PATTERN)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make staging directory: %w", err)
+	}
+	defer filesystem.RemoveAll(staging)
+
+	var result []Entry
+	for {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("bundle interrupted: %w", ctx.Err())
+		}
+
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read header: %w", err)
+		}
+
+		entry, ok := entries[header.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected file: %s", ErrInvalidBundle, header.Name)
+		}
+
+		err = readEntry(ctx, filesystem, reader, staging, entry)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read '%s': %w", entry.Path, err)
+		}
+
+		delete(entries, header.Name)
+		result = append(result, entry)
+	}
+
+	for _, entry := range manifest.Entries {
+		_, missing := entries[entry.Path]
+		if missing {
+			return nil, fmt.Errorf("%w: missing file: %s", ErrInvalidBundle, entry.Path)
+		}
+	}
+
+	for _, entry := range result {
+		err := moveEntry(filesystem, staging, root, entry)
+		if err != nil {
+			return nil, fmt.Errorf("cannot move '%s': %w", entry.Path, err)
+		}
+	}
+
+	return result, nil
+}
+
+func writeEntry(ctx context.Context, filesystem filesystem.Filesystem, writer *tar.Writer, root string, entry Entry) error {
+	file, err := filesystem.Open(filepath.Join(root, filepath.FromSlash(entry.Path)))
+	if err != nil {
+		return fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot stat file: %w", err)
+	}
+
+	err = writer.WriteHeader(&tar.Header{
+		Name:    entry.Path,
+		Mode:    utils.OS_FILE,
+		Size:    entry.Size,
+		ModTime: info.ModTime(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write header: %w", err)
+	}
+
+	_, err = io.CopyN(writer, utils.NewContextReader(ctx, file), entry.Size)
+	if err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	return nil
+}
+
+func readEntry(ctx context.Context, filesystem filesystem.Filesystem, reader io.Reader, staging string, entry Entry) error {
+	path, err := archiving.ResolvePath(staging, entry.Path)
+	if err != nil {
+		return fmt.Errorf("cannot resolve path: %w", err)
+	}
+
+	err = filesystem.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	file, err := filesystem.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, utils.OS_FILE)
+	if err != nil {
+		return fmt.Errorf("cannot create staging file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(file, hash), utils.NewContextReader(ctx, reader))
+	if err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if size != entry.Size || checksum != entry.Checksum {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, entry.Path)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close staging file: %w", err)
+	}
+
+	return nil
+}
+
+func moveEntry(filesystem filesystem.Filesystem, staging string, root string, entry Entry) error {
+	from, err := archiving.ResolvePath(staging, entry.Path)
+	if err != nil {
+		return fmt.Errorf("cannot resolve path: %w", err)
+	}
+
+	to, err := archiving.ResolvePath(root, entry.Path)
+	if err != nil {
+		return fmt.Errorf("cannot resolve path: %w", err)
+	}
+
+	err = filesystem.MkdirAll(filepath.Dir(to), utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	err = filesystem.Rename(from, to)
+	if err != nil {
+		return fmt.Errorf("cannot move file: %w", err)
+	}
+
+	return nil
+}
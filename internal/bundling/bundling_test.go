@@ -0,0 +1,114 @@
+package bundling
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/filesystem"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) []string {
+	t.Helper()
+
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("cannot make directory: %s", err)
+		}
+
+		err = os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("cannot write file: %s", err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func TestReadWrite(t *testing.T) {
+	source := t.TempDir()
+	target := t.TempDir()
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+
+	paths := writeFiles(t, source, map[string]string{
+		"godot/Godot_v4.3-stable_linux.x86_64.zip": "engine",
+		"index/api.github.com/4.3-stable.json":     "{}",
+	})
+
+	err := Write(context.Background(), filesystem.OS{}, bundlePath, source, paths)
+	if err != nil {
+		t.Fatalf("cannot write bundle: %s", err)
+	}
+
+	entries, err := Read(context.Background(), filesystem.OS{}, bundlePath, target)
+	if err != nil {
+		t.Fatalf("cannot read bundle: %s", err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("expected 2 entries but got %d", len(entries))
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(target, "godot", "Godot_v4.3-stable_linux.x86_64.zip"))
+	if err != nil || string(bytes) != "engine" {
+		t.Errorf("archive not imported: %v", err)
+	}
+}
+
+func TestReadChecksumMismatch(t *testing.T) {
+	source := t.TempDir()
+	target := t.TempDir()
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+
+	first := writeFiles(t, source, map[string]string{"godot/a.zip": "first"})
+	second := writeFiles(t, source, map[string]string{"godot/b.zip": "second"})
+
+	err := Write(context.Background(), filesystem.OS{}, bundlePath, source, append(first, second...))
+	if err != nil {
+		t.Fatalf("cannot write bundle: %s", err)
+	}
+
+	bytes, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatalf("cannot read bundle: %s", err)
+	}
+
+	index := len(bytes) - 1
+	for ; index >= 0; index-- {
+		if string(bytes[index:min(index+6, len(bytes))]) == "second" {
+			break
+		}
+	}
+
+	if index < 0 {
+		t.Fatalf("cannot find second entry")
+	}
+
+	copy(bytes[index:], "SECOND")
+
+	err = os.WriteFile(bundlePath, bytes, 0644)
+	if err != nil {
+		t.Fatalf("cannot corrupt bundle: %s", err)
+	}
+
+	_, err = Read(context.Background(), filesystem.OS{}, bundlePath, target)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch but got: %v", err)
+	}
+
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		t.Fatalf("cannot read target: %s", err)
+	}
+
+	for _, entry := range entries {
+		t.Errorf("unexpected file left in cache: %s", entry.Name())
+	}
+}
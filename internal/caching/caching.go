@@ -0,0 +1,62 @@
+package caching
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/internal/locking"
+	"github.com/bashmills/gevm/internal/utils"
+)
+
+func Locate(config *config.Config, folder string, name string) (string, error) {
+	writablePath := filepath.Join(config.CacheDirectory, folder, name)
+
+	exists, err := utils.DoesExist(config.Filesystem, writablePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if exists {
+		return writablePath, nil
+	}
+
+	for _, sharedDirectory := range config.SharedCacheDirectories {
+		sharedPath := filepath.Join(sharedDirectory, folder, name)
+
+		exists, err := utils.DoesExist(config.Filesystem, sharedPath)
+		if err != nil {
+			config.Logger.Warning("Failed to check shared cache: %s", err)
+			continue
+		}
+
+		if !exists {
+			continue
+		}
+
+		if !config.CopySharedCache {
+			config.Logger.Debug("Using shared cache: %s", sharedPath)
+			return sharedPath, nil
+		}
+
+		config.Logger.Debug("Copying from shared cache: %s", sharedPath)
+
+		err = utils.CopyFile(config.Filesystem, sharedPath, writablePath)
+		if err != nil {
+			return "", fmt.Errorf("cannot copy from shared cache: %w", err)
+		}
+
+		return writablePath, nil
+	}
+
+	return writablePath, nil
+}
+
+func Lock(ctx context.Context, config *config.Config, folder string, name string) (*locking.Lock, error) {
+	return locking.Acquire(ctx, config, filepath.Join(config.CacheDirectory, folder, name))
+}
+
+func TryLock(config *config.Config, folder string, name string) (*locking.Lock, bool, error) {
+	return locking.TryAcquire(config, filepath.Join(config.CacheDirectory, folder, name))
+}
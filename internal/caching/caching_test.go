@@ -0,0 +1,80 @@
+package caching
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/internal/fixtures"
+)
+
+const FOLDER = "godot"
+const NAME = "Godot_v4.3-stable_linux.x86_64.zip"
+
+func writeArchive(t *testing.T, directory string, content string) {
+	t.Helper()
+
+	path := filepath.Join(directory, FOLDER, NAME)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatalf("cannot make cache folder: %s", err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("cannot write archive: %s", err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Writable bool
+		Shared   []string
+		Copy     bool
+		Expected string
+		Content  string
+	}{
+		{Name: "missing", Expected: "cache"},
+		{Name: "writable", Writable: true, Shared: []string{"first"}, Expected: "cache", Content: "cache"},
+		{Name: "shared", Shared: []string{"second"}, Expected: "second", Content: "second"},
+		{Name: "shared priority", Shared: []string{"first", "second"}, Expected: "first", Content: "first"},
+		{Name: "shared copy", Shared: []string{"second"}, Copy: true, Expected: "cache", Content: "second"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			root := t.TempDir()
+			config := fixtures.NewConfig(t, root)
+			config.SharedCacheDirectories = []string{filepath.Join(root, "first"), filepath.Join(root, "second")}
+			config.CopySharedCache = test.Copy
+
+			if test.Writable {
+				writeArchive(t, config.CacheDirectory, "cache")
+			}
+
+			for _, shared := range test.Shared {
+				writeArchive(t, filepath.Join(root, shared), shared)
+			}
+
+			path, err := Locate(config, FOLDER, NAME)
+			if err != nil {
+				t.Fatalf("cannot locate archive: %s", err)
+			}
+
+			expected := filepath.Join(root, test.Expected, FOLDER, NAME)
+			if path != expected {
+				t.Errorf("expected '%s' but got '%s'", expected, path)
+			}
+
+			if len(test.Content) == 0 {
+				return
+			}
+
+			bytes, err := os.ReadFile(path)
+			if err != nil || string(bytes) != test.Content {
+				t.Errorf("expected '%s' but got: %s %v", test.Content, bytes, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package downloading
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,25 +10,28 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
 	"github.com/bashmills/gevm/internal/utils"
-	"github.com/bashmills/gevm/logger"
 	"github.com/schollz/progressbar/v3"
 )
 
+const PART_PATTERN = ".%s.*.part"
+
 var ErrNotFound = errors.New("not found")
 
-func Download(logger logger.Logger, url string, path string, silent bool) error {
-	exists, err := utils.DoesExist(path)
+func Download(ctx context.Context, config *config.Config, url string, path string) error {
+	exists, err := utils.DoesExist(config.Filesystem, path)
 	if err != nil {
 		return fmt.Errorf("failed to check existence: %w", err)
 	}
 
 	if exists {
-		logger.Info("Cached '%s' found", filepath.Base(path))
+		config.Logger.Info("Cached '%s' found", filepath.Base(path))
 		return nil
 	}
 
-	header, err := http.Head(url)
+	header, err := request(ctx, http.MethodHead, url)
 	if err != nil {
 		return fmt.Errorf("failed to request header: %w", err)
 	}
@@ -38,14 +42,15 @@ func Download(logger logger.Logger, url string, path string, silent bool) error
 		return fmt.Errorf("failed to parse header: %w", err)
 	}
 
-	logger.Info("Downloading '%s'", filepath.Base(path))
+	config.Logger.Info("Downloading '%s'", filepath.Base(path))
 
 	progress := progressbar.NewOptions64(size,
 		progressbar.OptionSetDescription(fmt.Sprintf("'%s'", filepath.Base(path))),
 		progressbar.OptionSetWidth(20),
 		progressbar.OptionShowBytes(true),
 		progressbar.OptionShowElapsedTimeOnFinish(),
-		progressbar.OptionOnCompletion(func() { fmt.Println() }),
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionOnCompletion(func() { fmt.Fprintln(os.Stderr) }),
 		progressbar.OptionSetTheme(progressbar.Theme{
 			Saucer:        "#",
 			SaucerHead:    "#",
@@ -53,10 +58,10 @@ func Download(logger logger.Logger, url string, path string, silent bool) error
 			BarStart:      "[",
 			BarEnd:        "]",
 		}),
-		progressbar.OptionSetVisibility(!silent),
+		progressbar.OptionSetVisibility(!config.Silent),
 	)
 
-	resp, err := http.Get(url)
+	resp, err := request(ctx, http.MethodGet, url)
 	if err != nil {
 		return fmt.Errorf("failed to request download: %w", err)
 	}
@@ -67,30 +72,49 @@ func Download(logger logger.Logger, url string, path string, silent bool) error
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("download status failure: %s", resp.Status)
+		return fmt.Errorf("download status failure: %w: %s", errs.ErrNetwork, resp.Status)
 	}
 
-	err = os.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
+	err = config.Filesystem.MkdirAll(filepath.Dir(path), utils.OS_DIRECTORY)
 	if err != nil {
 		return fmt.Errorf("could not make directory: %w", err)
 	}
 
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, utils.OS_FILE)
+	file, err := config.Filesystem.CreateTemp(filepath.Dir(path), fmt.Sprintf(PART_PATTERN, filepath.Base(path)))
 	if err != nil {
-		return fmt.Errorf("could not create destination file: %w", err)
+		return fmt.Errorf("could not create partial file: %w", err)
 	}
+	defer config.Filesystem.Remove(file.Name())
 	defer file.Close()
 
-	_, err = io.Copy(io.MultiWriter(file, progress), resp.Body)
+	_, err = io.Copy(io.MultiWriter(file, progress), utils.NewContextReader(ctx, resp.Body))
+	if ctx.Err() != nil {
+		return fmt.Errorf("download interrupted: %w", ctx.Err())
+	}
+	if err != nil {
+		return fmt.Errorf("could not copy file: %w: %w", errs.ErrNetwork, err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("could not close partial file: %w", err)
+	}
+
+	err = config.Filesystem.Chmod(file.Name(), utils.OS_FILE)
+	if err != nil {
+		return fmt.Errorf("could not set file permissions: %w", err)
+	}
+
+	err = config.Filesystem.Rename(file.Name(), path)
 	if err != nil {
-		return fmt.Errorf("could not copy file: %w", err)
+		return fmt.Errorf("could not move partial file: %w", err)
 	}
 
 	return nil
 }
 
-func Fetch(url string, callback func(http.Header, []byte) error) error {
-	resp, err := http.Get(url)
+func Fetch(ctx context.Context, url string, callback func(http.Header, []byte) error) error {
+	resp, err := request(ctx, http.MethodGet, url)
 	if err != nil {
 		return fmt.Errorf("failed to request fetch: %w", err)
 	}
@@ -101,12 +125,15 @@ func Fetch(url string, callback func(http.Header, []byte) error) error {
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("fetch status failure: %s", resp.Status)
+		return fmt.Errorf("fetch status failure: %w: %s", errs.ErrNetwork, resp.Status)
 	}
 
 	bytes, err := io.ReadAll(resp.Body)
+	if ctx.Err() != nil {
+		return fmt.Errorf("fetch interrupted: %w", ctx.Err())
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read body: %w", err)
+		return fmt.Errorf("failed to read body: %w: %w", errs.ErrNetwork, err)
 	}
 
 	err = callback(resp.Header, bytes)
@@ -116,3 +143,20 @@ func Fetch(url string, callback func(http.Header, []byte) error) error {
 
 	return nil
 }
+
+func request(ctx context.Context, method string, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errs.ErrNetwork, err)
+	}
+
+	return resp, nil
+}
@@ -1,15 +1,17 @@
 package environment
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
 
 	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/fetcher"
 	"github.com/bashmills/gevm/internal/downloading"
-	"github.com/bashmills/gevm/internal/environment/fetcher"
-	"github.com/bashmills/gevm/internal/platform"
-	"github.com/bashmills/gevm/internal/repository"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
 	"github.com/bashmills/gevm/semver"
 )
 
@@ -18,72 +20,191 @@ type Environment struct {
 	Config   *config.Config
 }
 
-func (e *Environment) FetchExportTemplatesAsset(semver semver.Semver) (*repository.Asset, error) {
-	var result *repository.Asset
+func (e *Environment) FetchExportTemplatesAsset(ctx context.Context, semver semver.Semver) (*repository.Asset, error) {
+	var notFound error = fmt.Errorf("%w: %s", errs.ErrVersionNotFound, semver.Relver.GodotString())
 	for _, fetcher := range e.Fetchers {
-		asset, err := fetcher.FetchAsset(platform.ExportTemplates, semver)
-		if errors.Is(err, downloading.ErrNotFound) {
+		asset, err := fetcher.FetchAsset(ctx, platform.ExportTemplates, semver)
+		if isNotFound(err) {
+			notFound = err
 			continue
 		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch export templates asset: %w", err)
 		}
 
-		result = asset
-		break
+		return asset, nil
 	}
 
-	if result == nil {
-		return nil, downloading.ErrNotFound
+	return nil, notFound
+}
+
+func (e *Environment) FetchGodotAsset(ctx context.Context, platform platform.Platform, semver semver.Semver) (*repository.Asset, error) {
+	var notFound error = fmt.Errorf("%w: %s", errs.ErrVersionNotFound, semver.Relver.GodotString())
+	for _, fetcher := range e.Fetchers {
+		asset, err := fetcher.FetchAsset(ctx, platform, semver)
+		if isNotFound(err) {
+			notFound = err
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch godot asset: %w", err)
+		}
+
+		return asset, nil
 	}
 
-	return result, nil
+	return nil, notFound
 }
 
-func (e *Environment) FetchGodotAsset(semver semver.Semver) (*repository.Asset, error) {
-	var result *repository.Asset
+// FetchDownloads merges the downloads of every fetcher. Fetchers are ordered
+// by priority so the first to offer a release, or an asset of a release for
+// some platform, wins and lower priority fetchers only fill in the gaps.
+func (e *Environment) FetchDownloads(ctx context.Context, mono bool) ([]repository.Download, error) {
+	var result []repository.Download
+	var failure error
+	found := false
+
 	for _, fetcher := range e.Fetchers {
-		asset, err := fetcher.FetchAsset(e.Config.Platform, semver)
-		if errors.Is(err, downloading.ErrNotFound) {
+		downloads, err := fetcher.FetchDownloads(ctx, mono)
+		if isNotFound(err) {
 			continue
 		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to fetch downloads: %w", ctx.Err())
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch godot asset: %w", err)
+			e.Config.Logger.Debug("Failed to fetch downloads: %s", err)
+			if failure == nil {
+				failure = err
+			}
+
+			continue
 		}
 
-		result = asset
-		break
+		found = true
+		result = merge(result, downloads)
+	}
+
+	if !found && failure != nil {
+		return nil, fmt.Errorf("failed to fetch downloads: %w", failure)
 	}
 
-	if result == nil {
-		return nil, downloading.ErrNotFound
+	if failure != nil {
+		e.Config.Logger.Warning("Some downloads may be missing: %s", failure)
 	}
 
+	if !found {
+		return nil, fmt.Errorf("no downloads found: %w", downloading.ErrNotFound)
+	}
+
+	slices.SortFunc(result, func(a repository.Download, b repository.Download) int { return a.Relver.Compare(b.Relver) })
+
 	return result, nil
 }
 
-func (e *Environment) FetchDownloads(mono bool) ([]repository.Download, error) {
+// FetchDownload looks up a single release, merging fetchers in the same way
+// as FetchDownloads. Fetchers that cannot look up a single release have their
+// full listing searched instead.
+func (e *Environment) FetchDownload(ctx context.Context, relver semver.Relver, mono bool) (*repository.Download, error) {
 	var result []repository.Download
+	var failure error
+
 	for _, fetcher := range e.Fetchers {
-		downloads, err := fetcher.FetchDownloads(mono)
-		if errors.Is(err, downloading.ErrNotFound) {
+		download, err := fetchDownload(ctx, fetcher, relver, mono)
+		if isNotFound(err) {
 			continue
 		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to fetch download: %w", ctx.Err())
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch downloads: %w", err)
+			e.Config.Logger.Debug("Failed to fetch download: %s", err)
+			if failure == nil {
+				failure = err
+			}
+
+			continue
 		}
 
-		result = downloads
-		break
+		result = merge(result, []repository.Download{*download})
 	}
 
-	if result == nil {
-		return nil, downloading.ErrNotFound
+	if len(result) == 0 && failure != nil {
+		return nil, fmt.Errorf("failed to fetch download: %w", failure)
 	}
 
-	slices.SortFunc(result, func(a repository.Download, b repository.Download) int { return a.Relver.Compare(b.Relver) })
+	if failure != nil {
+		e.Config.Logger.Warning("Some assets may be missing: %s", failure)
+	}
 
-	return result, nil
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, relver.GodotString())
+	}
+
+	return &result[0], nil
+}
+
+func fetchDownload(ctx context.Context, f fetcher.Fetcher, relver semver.Relver, mono bool) (*repository.Download, error) {
+	single, ok := f.(fetcher.DownloadFetcher)
+	if ok {
+		return single.FetchDownload(ctx, relver, mono)
+	}
+
+	downloads, err := f.FetchDownloads(ctx, mono)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(downloads, func(download repository.Download) bool { return download.Relver.Equal(relver) })
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, relver.GodotString())
+	}
+
+	return &downloads[index], nil
+}
+
+func merge(result []repository.Download, downloads []repository.Download) []repository.Download {
+	for _, download := range downloads {
+		index := slices.IndexFunc(result, func(existing repository.Download) bool { return existing.Relver.Equal(download.Relver) })
+		if index < 0 {
+			result = append(result, repository.Download{
+				Assets:     map[platform.Platform]repository.Asset{},
+				Relver:     download.Relver,
+				Published:  download.Published,
+				Notes:      download.Notes,
+				URL:        download.URL,
+				Prerelease: download.Prerelease,
+			})
+			index = len(result) - 1
+		}
+
+		existing := &result[index]
+		if existing.Published.IsZero() {
+			existing.Published = download.Published
+		}
+
+		if len(existing.Notes) == 0 {
+			existing.Notes = download.Notes
+		}
+
+		if len(existing.URL) == 0 {
+			existing.URL = download.URL
+		}
+
+		for platform, asset := range download.Assets {
+			if existing.HasAsset(platform) || !asset.IsValid() {
+				continue
+			}
+
+			existing.Assets[platform] = asset
+		}
+	}
+
+	return result
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, downloading.ErrNotFound) || errors.Is(err, errs.ErrVersionNotFound) || errors.Is(err, errs.ErrAssetNotFound)
 }
 
 func New(fetchers []fetcher.Fetcher, config *config.Config) (*Environment, error) {
@@ -0,0 +1,159 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+)
+
+func TestFetchDownloadsMerges(t *testing.T) {
+	custom := &fixtures.Fetcher{
+		Downloads: []repository.Download{
+			fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: "custom-linux"}),
+			fixtures.NewDownload("4.4-dev1", map[platform.Platform]string{platform.LinuxAmd64: "custom-dev"}),
+		},
+	}
+
+	github := &fixtures.Fetcher{
+		Downloads: []repository.Download{
+			fixtures.NewDownload("4.2-stable", map[platform.Platform]string{platform.LinuxAmd64: "github-old"}),
+			fixtures.NewDownload("4.3-stable", map[platform.Platform]string{
+				platform.LinuxAmd64:   "github-linux",
+				platform.WindowsAmd64: "github-windows",
+			}),
+		},
+	}
+
+	environment, err := New([]fetcher.Fetcher{custom, github}, fixtures.NewConfig(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	downloads, err := environment.FetchDownloads(context.Background(), false)
+	if err != nil {
+		t.Fatalf("cannot fetch downloads: %s", err)
+	}
+
+	var versions []string
+	for _, download := range downloads {
+		versions = append(versions, download.Relver.GodotString())
+	}
+
+	expected := []string{"4.2-stable", "4.3-stable", "4.4-dev1"}
+	if len(versions) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, versions)
+	}
+
+	for index := range expected {
+		if versions[index] != expected[index] {
+			t.Fatalf("expected %v but got %v", expected, versions)
+		}
+	}
+
+	merged := downloads[1].Assets
+	if merged[platform.LinuxAmd64].DownloadURL != "custom-linux" {
+		t.Errorf("higher priority asset not kept: %s", merged[platform.LinuxAmd64].DownloadURL)
+	}
+
+	if merged[platform.WindowsAmd64].DownloadURL != "github-windows" {
+		t.Errorf("lower priority asset not merged: %s", merged[platform.WindowsAmd64].DownloadURL)
+	}
+
+	if len(github.Downloads[1].Assets) != 2 || github.Downloads[1].Assets[platform.LinuxAmd64].DownloadURL != "github-linux" {
+		t.Errorf("fetcher downloads were modified")
+	}
+}
+
+func TestFetchDownloadsFailure(t *testing.T) {
+	failing := &fixtures.Fetcher{Err: errs.ErrNetwork}
+	working := &fixtures.Fetcher{
+		Downloads: []repository.Download{
+			fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: "linux"}),
+		},
+	}
+
+	environment, err := New([]fetcher.Fetcher{failing, working}, fixtures.NewConfig(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	downloads, err := environment.FetchDownloads(context.Background(), false)
+	if err != nil || len(downloads) != 1 {
+		t.Errorf("expected downloads from the working fetcher but got: %v %v", downloads, err)
+	}
+
+	environment, err = New([]fetcher.Fetcher{failing}, fixtures.NewConfig(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	_, err = environment.FetchDownloads(context.Background(), false)
+	if !errors.Is(err, errs.ErrNetwork) {
+		t.Errorf("expected network error but got: %v", err)
+	}
+}
+
+type fakeDownloadFetcher struct {
+	fixtures.Fetcher
+	Download *repository.Download
+}
+
+func (f *fakeDownloadFetcher) FetchDownloads(ctx context.Context, mono bool) ([]repository.Download, error) {
+	return nil, errs.ErrNetwork
+}
+
+func (f *fakeDownloadFetcher) FetchDownload(ctx context.Context, relver semver.Relver, mono bool) (*repository.Download, error) {
+	if f.Download == nil || !f.Download.Relver.Equal(relver) {
+		return nil, errs.ErrVersionNotFound
+	}
+
+	return f.Download, nil
+}
+
+func TestFetchDownload(t *testing.T) {
+	single := fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: "single-linux"})
+	custom := &fakeDownloadFetcher{Download: &single}
+	github := &fixtures.Fetcher{
+		Downloads: []repository.Download{
+			fixtures.NewDownload("4.2-stable", map[platform.Platform]string{platform.LinuxAmd64: "github-old"}),
+			fixtures.NewDownload("4.3-stable", map[platform.Platform]string{
+				platform.LinuxAmd64:   "github-linux",
+				platform.WindowsAmd64: "github-windows",
+			}),
+		},
+	}
+
+	environment, err := New([]fetcher.Fetcher{custom, github}, fixtures.NewConfig(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	relver, _ := semver.ParseRelver("4.3-stable")
+
+	download, err := environment.FetchDownload(context.Background(), relver, false)
+	if err != nil {
+		t.Fatalf("cannot fetch download: %s", err)
+	}
+
+	if download.Assets[platform.LinuxAmd64].DownloadURL != "single-linux" {
+		t.Errorf("higher priority asset not kept: %s", download.Assets[platform.LinuxAmd64].DownloadURL)
+	}
+
+	if download.Assets[platform.WindowsAmd64].DownloadURL != "github-windows" {
+		t.Errorf("lower priority asset not merged: %s", download.Assets[platform.WindowsAmd64].DownloadURL)
+	}
+
+	relver, _ = semver.ParseRelver("9.9-stable")
+
+	_, err = environment.FetchDownload(context.Background(), relver, false)
+	if !errors.Is(err, errs.ErrVersionNotFound) {
+		t.Errorf("expected version not found but got: %v", err)
+	}
+}
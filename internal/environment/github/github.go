@@ -1,45 +1,74 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/caching"
 	"github.com/bashmills/gevm/internal/downloading"
 	"github.com/bashmills/gevm/internal/environment/github/mappings"
-	"github.com/bashmills/gevm/internal/platform"
-	"github.com/bashmills/gevm/internal/repository"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
 	"github.com/bashmills/gevm/semver"
 )
 
-const REPOSITORY_URL = "https://api.github.com/repos/godotengine/godot-builds/releases?per_page=100"
-const ASSET_URL = "https://api.github.com/repos/godotengine/godot-builds/releases/tags/%s"
-const ASSET_REGEX_PATTERN = "([-_.]mono)?[-_.](export|linux|x11|windows|win|macos|osx)([-_.]?x86)?[-_.]?(templates|universal|fat|arm64|64)([-_.]?exe)?.(tpz|zip)"
+const RELEASES_PATH = "/releases?per_page=100"
+const RELEASE_PATH = "/releases/tags/%s"
+const ASSET_REGEX_PATTERN = "([-_.]mono)?[-_.](export|linuxbsd|linux|x11|windows|win|macos|osx)([-_.]?x86)?[-_.]?(templates|universal|fat|arm64|arm32|64|32)([-_.]?exe)?.(tpz|zip|tar[.]gz|tgz|tar[.]xz|txz|tar)"
 const NEXT_REGEX_PATTERN = "<([^>]*)>[^<]*(next)"
+const LAST_REGEX_PATTERN = "<([^>]*)>[^<]*(last)"
 const OLD_REGEX_PATTERN = "^(OLD)[-_.]"
+const SOURCE_REGEX_PATTERN = "[^A-Za-z0-9.-]+"
+const INDEX_FOLDER = "index"
+const FETCH_WORKERS = 4
+const CACHE_TTL = 10 * time.Minute
 
 var AssetRegex = regexp.MustCompile(ASSET_REGEX_PATTERN)
 var NextRegex = regexp.MustCompile(NEXT_REGEX_PATTERN)
+var LastRegex = regexp.MustCompile(LAST_REGEX_PATTERN)
 var OldRegex = regexp.MustCompile(OLD_REGEX_PATTERN)
+var SourceRegex = regexp.MustCompile(SOURCE_REGEX_PATTERN)
 
 type Github struct {
 	Config *config.Config
+
+	downloads map[bool][]repository.Download
+	fetched   time.Time
+	mutex     sync.Mutex
 }
 
 type Data struct {
-	Name   string `json:"tag_name"`
-	Assets []struct {
-		DownloadURL string `json:"browser_download_url"`
-		Name        string `json:"name"`
-	} `json:"assets"`
+	Name        string      `json:"tag_name"`
+	PublishedAt time.Time   `json:"published_at"`
+	Body        string      `json:"body"`
+	HTMLURL     string      `json:"html_url"`
+	Prerelease  bool        `json:"prerelease"`
+	Assets      []DataAsset `json:"assets"`
+}
+
+type DataAsset struct {
+	DownloadURL string `json:"browser_download_url"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
 }
 
-func (g *Github) FetchAsset(platform platform.Platform, semver semver.Semver) (*repository.Asset, error) {
+func (g *Github) FetchAsset(ctx context.Context, platform platform.Platform, semver semver.Semver) (*repository.Asset, error) {
 	g.Config.Logger.Trace("Fetching '%s' assets for platform: %s", semver.Relver.GodotString(), platform)
 
 	mapping, ok := mappings.Mappings[platform]
@@ -47,21 +76,12 @@ func (g *Github) FetchAsset(platform platform.Platform, semver semver.Semver) (*
 		return nil, fmt.Errorf("invalid platform mapping: %s", platform)
 	}
 
-	url := fmt.Sprintf(ASSET_URL, semver.Relver.GodotString())
-	var data Data
-
-	g.Config.Logger.Trace("Fetching data from url: %s", url)
-
-	err := downloading.Fetch(url, func(header http.Header, bytes []byte) error {
-		err := json.Unmarshal(bytes, &data)
-		if err != nil {
-			return fmt.Errorf("cannot parse bytes: %w", err)
-		}
-
-		return nil
-	})
+	data, err := g.fetchRelease(ctx, semver.Relver)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, semver.Relver.GodotString())
+	}
 	if err != nil {
-		return nil, fmt.Errorf("fetch failed: %w", err)
+		return nil, fmt.Errorf("fetch release failed: %w", err)
 	}
 
 	var assets []repository.Asset
@@ -100,11 +120,15 @@ func (g *Github) FetchAsset(platform platform.Platform, semver semver.Semver) (*
 		assets = append(assets, repository.Asset{
 			DownloadURL: asset.DownloadURL,
 			Name:        asset.Name,
+			Size:        asset.Size,
 		})
 	}
 
 	if len(assets) == 0 {
-		return nil, fmt.Errorf("fetch asset failure: %w", downloading.ErrNotFound)
+		return nil, &errs.AssetNotFoundError{
+			Platform: string(platform),
+			Version:  semver.GodotString(),
+		}
 	}
 
 	if len(assets) > 1 {
@@ -114,50 +138,85 @@ func (g *Github) FetchAsset(platform platform.Platform, semver semver.Semver) (*
 	return &assets[0], nil
 }
 
-func (g *Github) FetchDownloads(mono bool) ([]repository.Download, error) {
-	url := REPOSITORY_URL
-	var datas []Data
+func (g *Github) FetchDownloads(ctx context.Context, mono bool) ([]repository.Download, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
 
-	for {
-		g.Config.Logger.Trace("Fetching data from url: %s", url)
+	if g.downloads == nil || time.Since(g.fetched) > CACHE_TTL {
+		datas, err := g.fetchReleases(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetch releases failed: %w", err)
+		}
 
-		err := downloading.Fetch(url, func(header http.Header, bytes []byte) error {
-			var data []Data
-			err := json.Unmarshal(bytes, &data)
-			if err != nil {
-				return fmt.Errorf("cannot parse bytes: %w", err)
-			}
+		g.downloads = g.parseDownloads(datas)
+		g.fetched = time.Now()
+	}
 
-			datas = append(datas, data...)
-			link := header.Get("link")
+	return cloneDownloads(g.downloads[mono]), nil
+}
 
-			parts := NextRegex.FindStringSubmatch(link)
-			if len(parts) == 0 {
-				return io.EOF
-			}
+func (g *Github) FetchDownload(ctx context.Context, relver semver.Relver, mono bool) (*repository.Download, error) {
+	data, err := g.fetchRelease(ctx, relver)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, relver.GodotString())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch release failed: %w", err)
+	}
 
-			url = parts[1]
-			return nil
-		})
-		if errors.Is(err, io.EOF) {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("fetch failed: %w", err)
-		}
+	downloads := g.parseDownloads([]Data{*data})[mono]
+	if len(downloads) == 0 {
+		return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, relver.GodotString())
+	}
+
+	return &downloads[0], nil
+}
+
+// FetchIndex fetches the release index of a version into the cache directory
+// and returns its path.
+func (g *Github) FetchIndex(ctx context.Context, relver semver.Relver) (string, error) {
+	_, err := g.fetchRelease(ctx, relver)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return "", fmt.Errorf("%w: %s", errs.ErrVersionNotFound, relver.GodotString())
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetch release failed: %w", err)
 	}
 
-	var downloads []repository.Download
+	indexPath := IndexPath(g.Config, relver)
+
+	exists, err := utils.DoesExist(g.Config.Filesystem, indexPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if !exists {
+		return "", fmt.Errorf("release index not in cache directory: %w", os.ErrNotExist)
+	}
+
+	return indexPath, nil
+}
+
+func (g *Github) parseDownloads(datas []Data) map[bool][]repository.Download {
+	downloads := map[bool][]repository.Download{}
 
 	for _, data := range datas {
 		relver, err := semver.ParseRelver(data.Name)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse version release: %w", err)
+			g.Config.Logger.Warning("Could not parse version release: %s", err)
+			continue
 		}
 
-		download := repository.Download{
-			Assets: map[platform.Platform]repository.Asset{},
-			Relver: relver,
+		flavours := map[bool]repository.Download{}
+		for _, mono := range []bool{false, true} {
+			flavours[mono] = repository.Download{
+				Assets:     map[platform.Platform]repository.Asset{},
+				Relver:     relver,
+				Published:  data.PublishedAt,
+				Notes:      data.Body,
+				URL:        data.HTMLURL,
+				Prerelease: data.Prerelease,
+			}
 		}
 
 		for _, asset := range data.Assets {
@@ -170,10 +229,7 @@ func (g *Github) FetchDownloads(mono bool) ([]repository.Download, error) {
 				continue
 			}
 
-			isMono := len(parts[1]) > 0
-			if isMono != mono {
-				continue
-			}
+			download := flavours[len(parts[1]) > 0]
 
 			system := parts[2]
 			arch := parts[4]
@@ -204,6 +260,7 @@ func (g *Github) FetchDownloads(mono bool) ([]repository.Download, error) {
 				download.Assets[platform] = repository.Asset{
 					DownloadURL: asset.DownloadURL,
 					Name:        asset.Name,
+					Size:        asset.Size,
 				}
 
 				found = true
@@ -214,10 +271,282 @@ func (g *Github) FetchDownloads(mono bool) ([]repository.Download, error) {
 			}
 		}
 
-		downloads = append(downloads, download)
+		for _, mono := range []bool{false, true} {
+			downloads[mono] = append(downloads[mono], flavours[mono])
+		}
+	}
+
+	return downloads
+}
+
+func (g *Github) fetchReleases(ctx context.Context) ([]Data, error) {
+	url := g.sourceURL() + RELEASES_PATH
+
+	first, header, err := g.fetchPage(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch first page failed: %w", err)
+	}
+
+	link := header.Get("link")
+
+	last, err := lastPage(link)
+	if err != nil {
+		g.Config.Logger.Debug("Following pages in sequence: %s", err)
+		return g.followPages(ctx, first, link)
+	}
+
+	pages := make([][]Data, last)
+	pages[0] = first
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	failures := make(chan error, last)
+
+	var wait sync.WaitGroup
+	for range min(FETCH_WORKERS, last-1) {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+
+			for page := range jobs {
+				datas, _, err := g.fetchPage(ctx, pageURL(url, page))
+				if err != nil {
+					failures <- fmt.Errorf("fetch page %d failed: %w", page, err)
+					cancel()
+					continue
+				}
+
+				pages[page-1] = datas
+			}
+		}()
+	}
+
+	for page := 2; page <= last; page++ {
+		select {
+		case jobs <- page:
+		case <-ctx.Done():
+		}
+	}
+
+	close(jobs)
+	wait.Wait()
+	close(failures)
+
+	err = firstFailure(failures)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("fetch interrupted: %w", ctx.Err())
+	}
+
+	var datas []Data
+	for _, page := range pages {
+		datas = append(datas, page...)
+	}
+
+	return datas, nil
+}
+
+func (g *Github) followPages(ctx context.Context, datas []Data, link string) ([]Data, error) {
+	for {
+		parts := NextRegex.FindStringSubmatch(link)
+		if len(parts) == 0 {
+			return datas, nil
+		}
+
+		page, header, err := g.fetchPage(ctx, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("fetch page failed: %w", err)
+		}
+
+		datas = append(datas, page...)
+		link = header.Get("link")
+	}
+}
+
+func (g *Github) fetchPage(ctx context.Context, url string) ([]Data, http.Header, error) {
+	g.Config.Logger.Trace("Fetching data from url: %s", url)
+
+	var datas []Data
+	var header http.Header
+
+	err := downloading.Fetch(ctx, url, func(h http.Header, bytes []byte) error {
+		err := json.Unmarshal(bytes, &datas)
+		if err != nil {
+			return fmt.Errorf("cannot parse bytes: %w", err)
+		}
+
+		header = h
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch failed: %w", err)
 	}
 
-	return downloads, nil
+	return datas, header, nil
+}
+
+func (g *Github) fetchRelease(ctx context.Context, relver semver.Relver) (*Data, error) {
+	folder := IndexFolder(g.Config)
+	url := g.sourceURL() + fmt.Sprintf(RELEASE_PATH, relver.GodotString())
+
+	g.Config.Logger.Trace("Fetching data from url: %s", url)
+
+	var data Data
+
+	err := downloading.Fetch(ctx, url, func(header http.Header, bytes []byte) error {
+		err := json.Unmarshal(bytes, &data)
+		if err != nil {
+			return fmt.Errorf("cannot parse bytes: %w", err)
+		}
+
+		err = utils.WriteFileAtomic(g.Config.Filesystem, filepath.Join(g.Config.CacheDirectory, folder, IndexName(relver)), bytes)
+		if err != nil {
+			return fmt.Errorf("cannot write release index: %w", err)
+		}
+
+		return nil
+	})
+	if err == nil {
+		return &data, nil
+	}
+
+	if errors.Is(err, downloading.ErrNotFound) || ctx.Err() != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+
+	indexPath, lerr := caching.Locate(g.Config, folder, IndexName(relver))
+	if lerr != nil {
+		return nil, fmt.Errorf("cannot locate release index: %w", lerr)
+	}
+
+	exists, lerr := utils.DoesExist(g.Config.Filesystem, indexPath)
+	if lerr != nil {
+		return nil, fmt.Errorf("failed to check existence: %w", lerr)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+
+	g.Config.Logger.Debug("Cannot fetch release: %s", err)
+	g.Config.Logger.Debug("Using cached release index: %s", indexPath)
+
+	bytes, err := g.Config.Filesystem.ReadFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read release index: %w", err)
+	}
+
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse release index: %w", err)
+	}
+
+	return &data, nil
+}
+
+func (g *Github) sourceURL() string {
+	return SourceURL(g.Config.SourceURL)
+}
+
+func cloneDownloads(downloads []repository.Download) []repository.Download {
+	result := slices.Clone(downloads)
+	for index := range result {
+		result[index].Assets = maps.Clone(result[index].Assets)
+	}
+
+	return result
+}
+
+func lastPage(link string) (int, error) {
+	parts := LastRegex.FindStringSubmatch(link)
+	if len(parts) == 0 && NextRegex.MatchString(link) {
+		return 0, fmt.Errorf("no last page link")
+	}
+
+	if len(parts) == 0 {
+		return 1, nil
+	}
+
+	parsed, err := neturl.Parse(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid last page url: %w", err)
+	}
+
+	page, err := strconv.Atoi(parsed.Query().Get("page"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid last page: %w", err)
+	}
+
+	return max(page, 1), nil
+}
+
+func pageURL(url string, page int) string {
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return url
+	}
+
+	query := parsed.Query()
+	query.Set("page", strconv.Itoa(page))
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+func SourceURL(sourceURL string) string {
+	if len(sourceURL) > 0 {
+		return strings.TrimSuffix(sourceURL, "/")
+	}
+
+	return config.DEFAULT_SOURCE_URL
+}
+
+// IndexFolder keeps the release indexes of each source apart so switching
+// source-url never serves another host's download urls.
+func IndexFolder(config *config.Config) string {
+	url := SourceURL(config.SourceURL)
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	return filepath.Join(INDEX_FOLDER, SourceRegex.ReplaceAllString(url, "_"))
+}
+
+func IndexPath(config *config.Config, relver semver.Relver) string {
+	return filepath.Join(config.CacheDirectory, IndexFolder(config), IndexName(relver))
+}
+
+func IndexName(relver semver.Relver) string {
+	return fmt.Sprintf("%s.json", relver.GodotString())
+}
+
+func AssetPlatforms(name string) []platform.Platform {
+	parts := AssetRegex.FindStringSubmatch(name)
+	if len(parts) == 0 {
+		return nil
+	}
+
+	system := parts[2]
+	arch := parts[4]
+
+	var platforms []platform.Platform
+	for _, platform := range platform.Platforms {
+		mapping := mappings.Mappings[platform]
+		if slices.Index(mapping.System, system) < 0 {
+			continue
+		}
+
+		if slices.Index(mapping.Arch, arch) < 0 {
+			continue
+		}
+
+		platforms = append(platforms, platform)
+	}
+
+	return platforms
 }
 
 func New(config *config.Config) *Github {
@@ -225,3 +554,18 @@ func New(config *config.Config) *Github {
 		Config: config,
 	}
 }
+
+func firstFailure(failures <-chan error) error {
+	var result error
+	for err := range failures {
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		if result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
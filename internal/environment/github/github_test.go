@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+)
+
+const RELEASES_JSON = `[{"tag_name":"4.3-stable","assets":[{"browser_download_url":"http://localhost/Godot_v4.3-stable_linux.x86_64.zip","name":"Godot_v4.3-stable_linux.x86_64.zip","size":1}]}]`
+
+func newGithub(t *testing.T) (*Github, *int) {
+	t.Helper()
+
+	var requests int
+	var mutex sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		mutex.Unlock()
+
+		w.Write([]byte(RELEASES_JSON))
+	}))
+	t.Cleanup(server.Close)
+
+	config := fixtures.NewConfig(t, t.TempDir())
+	config.SourceURL = server.URL
+
+	return New(config), &requests
+}
+
+func TestFetchDownloadsClones(t *testing.T) {
+	github, _ := newGithub(t)
+
+	first, err := github.FetchDownloads(context.Background(), false)
+	if err != nil {
+		t.Fatalf("cannot fetch downloads: %s", err)
+	}
+
+	first[0].Assets[platform.LinuxAmd64] = first[0].Assets[platform.WindowsAmd64]
+	first[0].Notes = "modified"
+
+	second, err := github.FetchDownloads(context.Background(), false)
+	if err != nil {
+		t.Fatalf("cannot fetch downloads: %s", err)
+	}
+
+	if !second[0].HasAsset(platform.LinuxAmd64) || len(second[0].Notes) > 0 {
+		t.Errorf("memoised downloads were modified")
+	}
+}
+
+func TestFetchDownloadsExpires(t *testing.T) {
+	github, requests := newGithub(t)
+
+	for range 2 {
+		_, err := github.FetchDownloads(context.Background(), false)
+		if err != nil {
+			t.Fatalf("cannot fetch downloads: %s", err)
+		}
+	}
+
+	if *requests != 1 {
+		t.Errorf("expected downloads to be memoised but got %d requests", *requests)
+	}
+
+	github.fetched = time.Now().Add(-CACHE_TTL - time.Second)
+
+	_, err := github.FetchDownloads(context.Background(), false)
+	if err != nil {
+		t.Fatalf("cannot fetch downloads: %s", err)
+	}
+
+	if *requests != 2 {
+		t.Errorf("expected expired downloads to be fetched again but got %d requests", *requests)
+	}
+}
+
+func TestFirstFailure(t *testing.T) {
+	failures := make(chan error, 3)
+	failures <- fmt.Errorf("fetch page 2 failed: %w", context.Canceled)
+	failures <- fmt.Errorf("fetch page 3 failed: %w", errs.ErrNetwork)
+	failures <- fmt.Errorf("fetch page 4 failed: %w", context.Canceled)
+	close(failures)
+
+	err := firstFailure(failures)
+	if !errors.Is(err, errs.ErrNetwork) {
+		t.Errorf("expected the network failure but got: %v", err)
+	}
+
+	failures = make(chan error, 1)
+	failures <- fmt.Errorf("fetch page 2 failed: %w", context.Canceled)
+	close(failures)
+
+	err = firstFailure(failures)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation but got: %v", err)
+	}
+
+	failures = make(chan error)
+	close(failures)
+
+	err = firstFailure(failures)
+	if err != nil {
+		t.Errorf("expected no failure but got: %v", err)
+	}
+}
+
+func TestAssetPlatforms(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Expected []platform.Platform
+	}{
+		{Name: "Godot_v4.3-stable_linux.x86_64.zip", Expected: []platform.Platform{platform.LinuxAmd64}},
+		{Name: "Godot_v4.3-stable_linux.x86_32.zip", Expected: []platform.Platform{platform.LinuxX86}},
+		{Name: "Godot_v4.3-stable_linux.arm64.zip", Expected: []platform.Platform{platform.LinuxArm64}},
+		{Name: "Godot_v4.3-stable_linux.arm32.zip", Expected: []platform.Platform{platform.LinuxArm32}},
+		{Name: "Godot_v4.3-stable_mono_linux_x86_32.zip", Expected: []platform.Platform{platform.LinuxX86}},
+		{Name: "Godot_v4.0-beta1_linuxbsd.x86_64.zip", Expected: []platform.Platform{platform.LinuxAmd64}},
+		{Name: "Godot_v3.6-stable_x11.32.zip", Expected: []platform.Platform{platform.LinuxX86}},
+		{Name: "Godot_v4.3-stable_win32.exe.zip", Expected: []platform.Platform{platform.WindowsX86}},
+		{Name: "Godot_v4.3-stable_win64.exe.zip", Expected: []platform.Platform{platform.WindowsAmd64}},
+		{Name: "Godot_v4.3-stable_windows_arm64.exe.zip", Expected: []platform.Platform{platform.WindowsArm64}},
+		{Name: "Godot_v4.3-stable_macos.universal.zip", Expected: []platform.Platform{platform.DarwinArm64, platform.DarwinAmd64}},
+		{Name: "Godot_v3.2-stable_osx.64.zip", Expected: []platform.Platform{platform.DarwinAmd64}},
+		{Name: "Godot_v4.3-stable_export_templates.tpz", Expected: []platform.Platform{platform.ExportTemplates}},
+		{Name: "Godot_v4.3-stable_web_editor.zip"},
+	}
+
+	for _, test := range tests {
+		result := AssetPlatforms(test.Name)
+		if !slices.Equal(result, test.Expected) {
+			t.Errorf("expected %v for '%s' but got %v", test.Expected, test.Name, result)
+		}
+	}
+}
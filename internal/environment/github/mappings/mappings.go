@@ -1,6 +1,6 @@
 package mappings
 
-import "github.com/bashmills/gevm/internal/platform"
+import "github.com/bashmills/gevm/platform"
 
 type Mapping struct {
 	System []string
@@ -20,6 +20,10 @@ var Mappings = map[platform.Platform]Mapping{
 		System: []string{"windows", "win"},
 		Arch:   []string{"64"},
 	},
+	platform.WindowsX86: {
+		System: []string{"windows", "win"},
+		Arch:   []string{"32"},
+	},
 	platform.DarwinArm64: {
 		System: []string{"macos", "osx"},
 		Arch:   []string{"universal"},
@@ -29,13 +33,21 @@ var Mappings = map[platform.Platform]Mapping{
 		Arch:   []string{"universal", "fat", "64"},
 	},
 	platform.LinuxArm64: {
-		System: []string{"linux", "x11"},
+		System: []string{"linux", "linuxbsd", "x11"},
 		Arch:   []string{"arm64"},
 	},
+	platform.LinuxArm32: {
+		System: []string{"linux", "linuxbsd", "x11"},
+		Arch:   []string{"arm32"},
+	},
 	platform.LinuxAmd64: {
-		System: []string{"linux", "x11"},
+		System: []string{"linux", "linuxbsd", "x11"},
 		Arch:   []string{"64"},
 	},
+	platform.LinuxX86: {
+		System: []string{"linux", "linuxbsd", "x11"},
+		Arch:   []string{"32"},
+	},
 }
 
 var Overrides = map[platform.Platform][]string{
@@ -0,0 +1,114 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/logging"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+)
+
+// Fetcher offers the same downloads for both standard and mono requests.
+type Fetcher struct {
+	Downloads []repository.Download
+	Err       error
+}
+
+func (f *Fetcher) FetchAsset(ctx context.Context, platform platform.Platform, semver semver.Semver) (*repository.Asset, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	for _, download := range f.Downloads {
+		if !download.Relver.Equal(semver.Relver) {
+			continue
+		}
+
+		asset, ok := download.Assets[platform]
+		if !ok || !asset.IsValid() {
+			return nil, &errs.AssetNotFoundError{
+				Platform: string(platform),
+				Version:  semver.GodotString(),
+			}
+		}
+
+		return &asset, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errs.ErrVersionNotFound, semver.Relver.GodotString())
+}
+
+func (f *Fetcher) FetchDownloads(ctx context.Context, mono bool) ([]repository.Download, error) {
+	return f.Downloads, f.Err
+}
+
+// NewConfig returns a silent config with every directory inside root.
+func NewConfig(t testing.TB, root string) *config.Config {
+	t.Helper()
+
+	logger, err := logging.New(logging.NOTHING)
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	config, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("cannot create config: %s", err)
+	}
+
+	config.ExportTemplatesRootDirectory = filepath.Join(root, "templates")
+	config.GodotRootDirectory = filepath.Join(root, "godot")
+	config.CacheDirectory = filepath.Join(root, "cache")
+	config.BinDirectory = filepath.Join(root, "bin")
+	config.ConfigPath = filepath.Join(root, "config.json")
+	config.Platform = platform.LinuxAmd64
+	config.Logger = logger
+	config.Silent = true
+
+	return config
+}
+
+// NewDownload names each asset after the last element of its url.
+func NewDownload(version string, urls map[platform.Platform]string) repository.Download {
+	relver, _ := semver.ParseRelver(version)
+	download := repository.Download{
+		Assets: map[platform.Platform]repository.Asset{},
+		Relver: relver,
+	}
+
+	for platform, url := range urls {
+		download.Assets[platform] = repository.Asset{
+			DownloadURL: url,
+			Name:        path.Base(url),
+		}
+	}
+
+	return download
+}
+
+// NewServer serves each file at its path and responds not found otherwise.
+func NewServer(t testing.TB, files map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write(bytes)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
@@ -0,0 +1,115 @@
+package locator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+)
+
+const WINDOWS_REGEX_PATTERN = "Godot(.*?)[.]exe"
+const WINDOWS_CONSOLE_REGEX_PATTERN = "Godot(.*?)[-_.]console[.]exe"
+const WINDOWS_INVALID_REGEX_PATTERN = "(console)[.]exe"
+const DARWIN_REGEX_PATTERN = "Godot(.*?)[.]app"
+const DARWIN_BUNDLE_PATH = "Contents/MacOS/Godot"
+const LINUX_REGEX_PATTERN = "Godot(.*?)([-_.]mono)?[-_.](linuxbsd|linux|x11)([-_.]?x86)?[-_.]?(arm64|arm32|64|32)"
+
+type Pattern struct {
+	Executable *regexp.Regexp
+	Invalid    *regexp.Regexp
+	Console    *regexp.Regexp
+	Bundle     string
+}
+
+var Windows = Pattern{
+	Executable: regexp.MustCompile(WINDOWS_REGEX_PATTERN),
+	Invalid:    regexp.MustCompile(WINDOWS_INVALID_REGEX_PATTERN),
+	Console:    regexp.MustCompile(WINDOWS_CONSOLE_REGEX_PATTERN),
+}
+
+var Darwin = Pattern{
+	Executable: regexp.MustCompile(DARWIN_REGEX_PATTERN),
+	Bundle:     DARWIN_BUNDLE_PATH,
+}
+
+var Linux = Pattern{
+	Executable: regexp.MustCompile(LINUX_REGEX_PATTERN),
+}
+
+var Patterns = map[platform.Platform]Pattern{
+	platform.WindowsArm64: Windows,
+	platform.WindowsAmd64: Windows,
+	platform.WindowsX86:   Windows,
+	platform.DarwinArm64:  Darwin,
+	platform.DarwinAmd64:  Darwin,
+	platform.LinuxArm64:   Linux,
+	platform.LinuxArm32:   Linux,
+	platform.LinuxAmd64:   Linux,
+	platform.LinuxX86:     Linux,
+}
+
+type Locator struct {
+	Config *config.Config
+}
+
+func (l *Locator) Find(semver semver.Semver, platform platform.Platform, console bool) (string, error) {
+	return l.Locate(filepath.Join(l.Config.GodotRootDirectory, semver.GodotString()), platform, console)
+}
+
+func (l *Locator) Locate(root string, platform platform.Platform, console bool) (string, error) {
+	pattern, ok := Patterns[platform]
+	if !ok {
+		return "", fmt.Errorf("no executable pattern for platform: %s", platform)
+	}
+
+	if console && pattern.Console != nil {
+		path, err := utils.LocateExecutable(l.Config.Filesystem, func(filename string) bool {
+			return pattern.Console.MatchString(filename)
+		}, root, false)
+		if !errors.Is(err, os.ErrNotExist) {
+			return path, err
+		}
+
+		l.Config.Logger.Debug("No console executable found so using the regular one: %s", root)
+	}
+
+	path, err := utils.LocateExecutable(l.Config.Filesystem, func(filename string) bool {
+		if pattern.Invalid != nil && pattern.Invalid.MatchString(filename) {
+			return false
+		}
+
+		return pattern.Executable.MatchString(filename)
+	}, root, len(pattern.Bundle) > 0)
+	if err != nil {
+		return "", err
+	}
+
+	if len(pattern.Bundle) == 0 {
+		return path, nil
+	}
+
+	path = filepath.Join(path, filepath.FromSlash(pattern.Bundle))
+
+	exists, err := utils.DoesExist(l.Config.Filesystem, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if !exists {
+		return "", fmt.Errorf("executable not found in bundle: %w", os.ErrNotExist)
+	}
+
+	return path, nil
+}
+
+func New(config *config.Config) (*Locator, error) {
+	return &Locator{
+		Config: config,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package locator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+)
+
+func newLocator(t *testing.T) *Locator {
+	t.Helper()
+
+	locator, err := New(fixtures.NewConfig(t, t.TempDir()))
+	if err != nil {
+		t.Fatalf("cannot create locator: %s", err)
+	}
+
+	return locator
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Platform platform.Platform
+		Console  bool
+		Files    []string
+		Expected string
+	}{
+		{
+			Name:     "windows",
+			Platform: platform.WindowsAmd64,
+			Files:    []string{"Godot_v4.3-stable_win64_console.exe", "Godot_v4.3-stable_win64.exe"},
+			Expected: "Godot_v4.3-stable_win64.exe",
+		},
+		{
+			Name:     "windows console",
+			Platform: platform.WindowsAmd64,
+			Console:  true,
+			Files:    []string{"Godot_v4.3-stable_win64.exe", "Godot_v4.3-stable_win64_console.exe"},
+			Expected: "Godot_v4.3-stable_win64_console.exe",
+		},
+		{
+			Name:     "windows console fallback",
+			Platform: platform.WindowsX86,
+			Console:  true,
+			Files:    []string{"Godot_v3.6-stable_win32.exe"},
+			Expected: "Godot_v3.6-stable_win32.exe",
+		},
+		{
+			Name:     "windows mono",
+			Platform: platform.WindowsAmd64,
+			Files:    []string{"Godot_v4.3-stable_mono_win64/GodotSharp/Api/Debug/GodotSharp.dll", "Godot_v4.3-stable_mono_win64/Godot_v4.3-stable_mono_win64.exe"},
+			Expected: "Godot_v4.3-stable_mono_win64/Godot_v4.3-stable_mono_win64.exe",
+		},
+		{
+			Name:     "darwin bundle",
+			Platform: platform.DarwinArm64,
+			Files:    []string{"Godot.app/Contents/Info.plist", "Godot.app/Contents/MacOS/Godot"},
+			Expected: "Godot.app/Contents/MacOS/Godot",
+		},
+		{
+			Name:     "darwin console",
+			Platform: platform.DarwinAmd64,
+			Console:  true,
+			Files:    []string{"Godot_mono.app/Contents/MacOS/Godot"},
+			Expected: "Godot_mono.app/Contents/MacOS/Godot",
+		},
+		{
+			Name:     "darwin bundle without executable",
+			Platform: platform.DarwinArm64,
+			Files:    []string{"Godot.app/Contents/Info.plist"},
+		},
+		{
+			Name:     "linux",
+			Platform: platform.LinuxAmd64,
+			Files:    []string{"Godot_v4.3-stable_linux.x86_64"},
+			Expected: "Godot_v4.3-stable_linux.x86_64",
+		},
+		{
+			Name:     "linux mono",
+			Platform: platform.LinuxArm64,
+			Console:  true,
+			Files:    []string{"Godot_v4.3-stable_mono_linux_arm64/Godot_v4.3-stable_mono_linux.arm64"},
+			Expected: "Godot_v4.3-stable_mono_linux_arm64/Godot_v4.3-stable_mono_linux.arm64",
+		},
+		{
+			Name:     "missing",
+			Platform: platform.LinuxAmd64,
+			Files:    []string{"README.md"},
+		},
+	}
+
+	locator := newLocator(t)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, file := range test.Files {
+				path := filepath.Join(root, filepath.FromSlash(file))
+				err := os.MkdirAll(filepath.Dir(path), 0755)
+				if err != nil {
+					t.Fatalf("cannot make directory: %s", err)
+				}
+
+				err = os.WriteFile(path, []byte("godot"), 0755)
+				if err != nil {
+					t.Fatalf("cannot write file: %s", err)
+				}
+			}
+
+			path, err := locator.Locate(root, test.Platform, test.Console)
+			if len(test.Expected) == 0 {
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("expected not found but got: %s %v", path, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("cannot locate executable: %s", err)
+			}
+
+			expected := filepath.Join(root, filepath.FromSlash(test.Expected))
+			if path != expected {
+				t.Errorf("expected '%s' but got '%s'", expected, path)
+			}
+		})
+	}
+
+	_, err := locator.Locate(t.TempDir(), platform.ExportTemplates, false)
+	if err == nil {
+		t.Errorf("expected export templates to have no executable")
+	}
+}
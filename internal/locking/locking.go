@@ -0,0 +1,154 @@
+package locking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/filesystem"
+	"github.com/bashmills/gevm/internal/utils"
+)
+
+const POLL_INTERVAL = 250 * time.Millisecond
+
+var ErrTimeout = errs.ErrLockTimeout
+
+type Lock struct {
+	File filesystem.File
+}
+
+func (l *Lock) Release() error {
+	fd, ok := filesystem.DescriptorOf(l.File)
+	if ok {
+		err := unlock(fd)
+		if err != nil {
+			l.File.Close()
+			return fmt.Errorf("cannot unlock file: %w", err)
+		}
+	}
+
+	err := l.File.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close lock file: %w", err)
+	}
+
+	return nil
+}
+
+func Acquire(ctx context.Context, config *config.Config, path string) (*Lock, error) {
+	timeout, err := utils.ParseDuration(config.LockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lock timeout: %w", err)
+	}
+
+	lockPath := LockPath(path)
+
+	file, fd, ok, err := open(config, lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return &Lock{
+			File: file,
+		}, nil
+	}
+
+	config.Logger.Trace("Attempting to acquire lock: %s", lockPath)
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+
+	for {
+		locked, err := tryLock(fd)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("cannot lock file: %w", err)
+		}
+
+		if locked {
+			config.Logger.Trace("Lock acquired: %s", lockPath)
+			return &Lock{
+				File: file,
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w: %s", ErrTimeout, lockPath)
+		}
+
+		if !waiting {
+			config.Logger.Info("Waiting for another gevm process to finish with '%s'", filepath.Base(path))
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, fmt.Errorf("cannot acquire lock: %w", ctx.Err())
+		case <-time.After(POLL_INTERVAL):
+		}
+	}
+}
+
+// TryAcquire attempts to take the lock once without waiting and reports
+// whether it was taken. The lock is also busy while it is held elsewhere
+// in this process.
+func TryAcquire(config *config.Config, path string) (*Lock, bool, error) {
+	lockPath := LockPath(path)
+
+	file, fd, ok, err := open(config, lockPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !ok {
+		return &Lock{
+			File: file,
+		}, true, nil
+	}
+
+	locked, err := tryLock(fd)
+	if err != nil {
+		file.Close()
+		return nil, false, fmt.Errorf("cannot lock file: %w", err)
+	}
+
+	if !locked {
+		file.Close()
+		return nil, false, nil
+	}
+
+	config.Logger.Trace("Lock acquired: %s", lockPath)
+	return &Lock{
+		File: file,
+	}, true, nil
+}
+
+func open(config *config.Config, lockPath string) (filesystem.File, uintptr, bool, error) {
+	err := config.Filesystem.MkdirAll(filepath.Dir(lockPath), utils.OS_DIRECTORY)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	file, err := config.Filesystem.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, utils.OS_FILE)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("cannot open lock file: %w", err)
+	}
+
+	fd, ok := filesystem.DescriptorOf(file)
+	if !ok {
+		config.Logger.Trace("Filesystem does not support locking: %s", lockPath)
+	}
+
+	return file, fd, ok, nil
+}
+
+func LockPath(path string) string {
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.lock", filepath.Base(path)))
+}
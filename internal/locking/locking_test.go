@@ -0,0 +1,56 @@
+package locking
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bashmills/gevm/internal/fixtures"
+)
+
+func TestAcquire(t *testing.T) {
+	config := fixtures.NewConfig(t, t.TempDir())
+	config.LockTimeout = "1ms"
+	path := filepath.Join(config.CacheDirectory, "godot", "Godot_v4.3-stable_linux.x86_64.zip")
+
+	lock, err := Acquire(context.Background(), config, path)
+	if err != nil {
+		t.Fatalf("cannot acquire lock: %s", err)
+	}
+
+	_, locked, err := TryAcquire(config, path)
+	if err != nil || locked {
+		t.Errorf("expected held lock to be busy but got: %t %v", locked, err)
+	}
+
+	_, err = Acquire(context.Background(), config, path)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected lock timeout but got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config.LockTimeout = "10m"
+
+	_, err = Acquire(ctx, config, path)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation but got: %v", err)
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Fatalf("cannot release lock: %s", err)
+	}
+
+	lock, locked, err = TryAcquire(config, path)
+	if err != nil || !locked {
+		t.Fatalf("expected released lock to be taken but got: %t %v", locked, err)
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Errorf("cannot release lock: %s", err)
+	}
+}
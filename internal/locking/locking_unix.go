@@ -0,0 +1,24 @@
+//go:build unix
+
+package locking
+
+import (
+	"errors"
+	"syscall"
+)
+
+func tryLock(fd uintptr) (bool, error) {
+	err := syscall.Flock(int(fd), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func unlock(fd uintptr) error {
+	return syscall.Flock(int(fd), syscall.LOCK_UN)
+}
@@ -1,9 +1,14 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/bashmills/gevm/internal/utils"
 	"github.com/bashmills/gevm/logger"
 )
 
@@ -18,56 +23,139 @@ const (
 	TRACE
 )
 
+func (l Level) String() string {
+	switch l {
+	case ERROR:
+		return "error"
+	case WARNING:
+		return "warning"
+	case INFO:
+		return "info"
+	case DEBUG:
+		return "debug"
+	case TRACE:
+		return "trace"
+	}
+
+	return "nothing"
+}
+
+type Format string
+
+const (
+	TEXT Format = "text"
+	JSON Format = "json"
+)
+
+type Option func(*Logging)
+
 type Logging struct {
-	Level Level
+	Level  Level
+	Writer io.Writer
+	Format Format
+
+	mutex sync.Mutex
 }
 
-func (l Logging) Error(format string, a ...any) {
-	if !l.ShouldLog(ERROR) {
-		return
-	}
+func (l *Logging) Error(format string, a ...any) {
+	l.log(ERROR, format, a...)
+}
 
-	utils.Printlnf("ERROR: %s", fmt.Sprintf(format, a...))
+func (l *Logging) Warning(format string, a ...any) {
+	l.log(WARNING, format, a...)
 }
 
-func (l Logging) Warning(format string, a ...any) {
-	if !l.ShouldLog(WARNING) {
-		return
-	}
+func (l *Logging) Info(format string, a ...any) {
+	l.log(INFO, format, a...)
+}
+
+func (l *Logging) Debug(format string, a ...any) {
+	l.log(DEBUG, format, a...)
+}
+
+func (l *Logging) Trace(format string, a ...any) {
+	l.log(TRACE, format, a...)
+}
 
-	utils.Printlnf("WARNING: %s", fmt.Sprintf(format, a...))
+func (l *Logging) ShouldLog(level Level) bool {
+	return l.Level >= level
 }
 
-func (l Logging) Info(format string, a ...any) {
-	if !l.ShouldLog(INFO) {
+func (l *Logging) log(level Level, format string, a ...any) {
+	if !l.ShouldLog(level) {
 		return
 	}
 
-	utils.Printlnf(format, a...)
+	message := fmt.Sprintf(format, a...)
+
+	var line string
+	switch l.Format {
+	default:
+		line = formatText(level, message)
+	case JSON:
+		line = formatJson(level, message)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	fmt.Fprintln(l.Writer, line)
 }
 
-func (l Logging) Debug(format string, a ...any) {
-	if !l.ShouldLog(DEBUG) {
-		return
+func formatText(level Level, message string) string {
+	switch level {
+	case ERROR, WARNING:
+		return fmt.Sprintf("%s: %s", strings.ToUpper(level.String()), message)
 	}
 
-	utils.Printlnf(format, a...)
+	return message
 }
 
-func (l Logging) Trace(format string, a ...any) {
-	if !l.ShouldLog(TRACE) {
-		return
+func formatJson(level Level, message string) string {
+	bytes, err := json.Marshal(struct {
+		Time    string `json:"time"`
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}{
+		Time:    time.Now().Format(time.RFC3339),
+		Level:   level.String(),
+		Message: message,
+	})
+	if err != nil {
+		return formatText(level, message)
 	}
 
-	utils.Printlnf(format, a...)
+	return string(bytes)
 }
 
-func (l Logging) ShouldLog(level Level) bool {
-	return l.Level >= level
+func OptionSetWriter(writer io.Writer) Option {
+	return func(l *Logging) {
+		l.Writer = writer
+	}
 }
 
-func New(level Level) (logger.Logger, error) {
-	return &Logging{
-		Level: level,
-	}, nil
+func OptionSetFormat(format Format) Option {
+	return func(l *Logging) {
+		l.Format = format
+	}
+}
+
+func New(level Level, options ...Option) (logger.Logger, error) {
+	logging := &Logging{
+		Level:  level,
+		Writer: os.Stderr,
+		Format: TEXT,
+	}
+
+	for _, option := range options {
+		option(logging)
+	}
+
+	switch logging.Format {
+	default:
+		return nil, fmt.Errorf("log format not handled: %s", logging.Format)
+	case TEXT, JSON:
+	}
+
+	return logging, nil
 }
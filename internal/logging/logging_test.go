@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewDefaultsToStderr(t *testing.T) {
+	logger, err := New(INFO)
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	if logger.(*Logging).Writer != os.Stderr {
+		t.Errorf("expected logs to be written to stderr")
+	}
+
+	_, err = New(INFO, OptionSetFormat("xml"))
+	if err == nil {
+		t.Errorf("expected unknown log format to fail")
+	}
+}
+
+func TestLogText(t *testing.T) {
+	var buffer bytes.Buffer
+
+	logger, err := New(INFO, OptionSetWriter(&buffer))
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	logger.Warning("Cache is %d%% full", 90)
+	logger.Info("Installed '%s'", "4.3-stable")
+	logger.Debug("Not shown")
+
+	expected := "WARNING: Cache is 90% full\nInstalled '4.3-stable'\n"
+	if buffer.String() != expected {
+		t.Errorf("expected %q but got %q", expected, buffer.String())
+	}
+}
+
+func TestLogJson(t *testing.T) {
+	var buffer bytes.Buffer
+
+	logger, err := New(DEBUG, OptionSetWriter(&buffer), OptionSetFormat(JSON))
+	if err != nil {
+		t.Fatalf("cannot create logger: %s", err)
+	}
+
+	logger.Error("Download failed")
+	logger.Debug("Retrying \"%s\"", "4.3-stable")
+	logger.Trace("Not shown")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines but got: %q", buffer.String())
+	}
+
+	expected := []struct {
+		Level   string
+		Message string
+	}{
+		{Level: "error", Message: "Download failed"},
+		{Level: "debug", Message: "Retrying \"4.3-stable\""},
+	}
+
+	for index, line := range lines {
+		var entry struct {
+			Time    string `json:"time"`
+			Level   string `json:"level"`
+			Message string `json:"message"`
+		}
+
+		err := json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatalf("cannot parse log line '%s': %s", line, err)
+		}
+
+		if entry.Level != expected[index].Level || entry.Message != expected[index].Message || len(entry.Time) == 0 {
+			t.Errorf("expected %v but got: %s", expected[index], line)
+		}
+	}
+}
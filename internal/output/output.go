@@ -0,0 +1,362 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	TABLE    Format = "table"
+	JSON     Format = "json"
+	YAML     Format = "yaml"
+	CSV      Format = "csv"
+	MARKDOWN Format = "markdown"
+)
+
+var HighlightColors = text.Colors{text.Bold, text.FgYellow}
+
+var Formats = []Format{
+	TABLE,
+	JSON,
+	YAML,
+	CSV,
+	MARKDOWN,
+}
+
+type Column struct {
+	Key       string
+	Title     string
+	Formatter func(any) string
+}
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Summary holds values describing the listing as a whole. The json and yaml
+// formats wrap the rows in an object holding these along with the rows under
+// Items while csv leaves them out to keep every record the same shape.
+type Table struct {
+	Columns     []Column
+	Rows        [][]any
+	Footer      []any
+	Highlighted map[int]bool
+	Summary     []Field
+	Items       string
+}
+
+func (t *Table) AppendRow(row ...any) {
+	t.Rows = append(t.Rows, row)
+}
+
+func (t *Table) AppendHighlightedRow(row ...any) {
+	if t.Highlighted == nil {
+		t.Highlighted = map[int]bool{}
+	}
+
+	t.Highlighted[len(t.Rows)] = true
+	t.AppendRow(row...)
+}
+
+func (t *Table) IsEmpty() bool {
+	return len(t.Rows) == 0
+}
+
+func Render(format Format, writer io.Writer, t *Table) error {
+	switch format {
+	default:
+		return fmt.Errorf("output format not handled: %s", format)
+	case TABLE:
+		renderPretty(writer, t, isTerminal(writer)).Render()
+	case MARKDOWN:
+		renderPretty(writer, t, false).RenderMarkdown()
+	case CSV:
+		return renderCsv(writer, t)
+	case JSON:
+		return renderJson(writer, t)
+	case YAML:
+		return renderYaml(writer, t)
+	}
+
+	return nil
+}
+
+func renderPretty(writer io.Writer, t *Table, colored bool) table.Writer {
+	w := table.NewWriter()
+
+	var header table.Row
+	for _, column := range t.Columns {
+		header = append(header, column.Title)
+	}
+
+	w.AppendHeader(header)
+
+	for rowIndex, row := range t.Rows {
+		var cells table.Row
+		for index, value := range row {
+			cell := display(t.Columns[index], value)
+			if colored && t.Highlighted[rowIndex] {
+				cell = HighlightColors.Sprint(cell)
+			}
+
+			cells = append(cells, cell)
+		}
+
+		w.AppendRow(cells)
+	}
+
+	if len(t.Footer) > 0 {
+		w.AppendFooter(t.Footer)
+		w.Style().Format.Footer = text.FormatDefault
+	}
+
+	w.SetOutputMirror(writer)
+
+	return w
+}
+
+func renderCsv(writer io.Writer, t *Table) error {
+	w := csv.NewWriter(writer)
+
+	var header []string
+	for _, column := range t.Columns {
+		header = append(header, column.Key)
+	}
+
+	err := w.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write header: %w", err)
+	}
+
+	for _, row := range t.Rows {
+		var record []string
+		for _, value := range row {
+			record = append(record, scalar(value))
+		}
+
+		err := w.Write(record)
+		if err != nil {
+			return fmt.Errorf("cannot write record: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func renderJson(writer io.Writer, t *Table) error {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("[")
+	for rowIndex, row := range t.Rows {
+		if rowIndex > 0 {
+			buffer.WriteString(",")
+		}
+
+		buffer.WriteString("{")
+		for index, value := range row {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+
+			err := writeJsonField(&buffer, t.Columns[index].Key, normalize(value))
+			if err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("}")
+	}
+	buffer.WriteString("]")
+
+	if len(t.Summary) > 0 {
+		var wrapped bytes.Buffer
+
+		wrapped.WriteString("{")
+		for _, field := range t.Summary {
+			err := writeJsonField(&wrapped, field.Key, normalize(field.Value))
+			if err != nil {
+				return err
+			}
+
+			wrapped.WriteString(",")
+		}
+
+		err := writeJsonField(&wrapped, t.Items, json.RawMessage(buffer.Bytes()))
+		if err != nil {
+			return err
+		}
+		wrapped.WriteString("}")
+
+		buffer = wrapped
+	}
+
+	var indented bytes.Buffer
+
+	err := json.Indent(&indented, buffer.Bytes(), "", "	")
+	if err != nil {
+		return fmt.Errorf("cannot indent json: %w", err)
+	}
+
+	indented.WriteString("\n")
+
+	_, err = indented.WriteTo(writer)
+	if err != nil {
+		return fmt.Errorf("cannot write json: %w", err)
+	}
+
+	return nil
+}
+
+func writeJsonField(buffer *bytes.Buffer, key string, value any) error {
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("cannot encode key: %w", err)
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot encode value: %w", err)
+	}
+
+	buffer.Write(keyBytes)
+	buffer.WriteString(":")
+	buffer.Write(valueBytes)
+
+	return nil
+}
+
+func renderYaml(writer io.Writer, t *Table) error {
+	document := &yaml.Node{
+		Kind: yaml.SequenceNode,
+	}
+
+	for _, row := range t.Rows {
+		mapping := &yaml.Node{
+			Kind: yaml.MappingNode,
+		}
+
+		for index, value := range row {
+			var node yaml.Node
+
+			err := node.Encode(normalize(value))
+			if err != nil {
+				return fmt.Errorf("cannot encode value: %w", err)
+			}
+
+			mapping.Content = append(mapping.Content, &yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Value: t.Columns[index].Key,
+			}, &node)
+		}
+
+		document.Content = append(document.Content, mapping)
+	}
+
+	if len(t.Summary) > 0 {
+		wrapped := &yaml.Node{
+			Kind: yaml.MappingNode,
+		}
+
+		for _, field := range t.Summary {
+			var node yaml.Node
+
+			err := node.Encode(normalize(field.Value))
+			if err != nil {
+				return fmt.Errorf("cannot encode value: %w", err)
+			}
+
+			wrapped.Content = append(wrapped.Content, &yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Value: field.Key,
+			}, &node)
+		}
+
+		wrapped.Content = append(wrapped.Content, &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Value: t.Items,
+		}, document)
+
+		document = wrapped
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(document)
+	if err != nil {
+		return fmt.Errorf("cannot write yaml: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func display(column Column, value any) any {
+	if column.Formatter != nil {
+		return column.Formatter(value)
+	}
+
+	switch value := normalize(value).(type) {
+	case []string:
+		return strings.Join(value, ", ")
+	default:
+		return value
+	}
+}
+
+func scalar(value any) string {
+	switch value := normalize(value).(type) {
+	case []string:
+		return strings.Join(value, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func normalize(value any) any {
+	switch value := value.(type) {
+	case nil, bool, int, int64, float64, string:
+		return value
+	case []string:
+		if value == nil {
+			return []string{}
+		}
+
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func newTable() *Table {
+	t := &Table{
+		Columns: []Column{
+			{Key: "name", Title: "Name"},
+			{Key: "platforms", Title: "Platforms"},
+			{Key: "size", Title: "Size"},
+			{Key: "downloaded", Title: "Downloaded"},
+		},
+		Summary: []Field{{Key: "total", Value: int64(300)}},
+		Items:   "archives",
+	}
+
+	downloaded := time.Date(2024, time.August, 15, 12, 0, 0, 0, time.UTC)
+	t.AppendRow("Godot_v4.3-stable_linux.x86_64.zip", []string{"linux-amd64", "linux-arm64"}, int64(100), downloaded)
+	t.AppendRow("Godot_v4.3-stable_export_templates.tpz", []string(nil), int64(200), downloaded)
+
+	return t
+}
+
+func TestRenderCsv(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := Render(CSV, &buffer, newTable())
+	if err != nil {
+		t.Fatalf("cannot render csv: %s", err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("cannot read csv: %s", err)
+	}
+
+	expected := [][]string{
+		{"name", "platforms", "size", "downloaded"},
+		{"Godot_v4.3-stable_linux.x86_64.zip", "linux-amd64,linux-arm64", "100", "2024-08-15T12:00:00Z"},
+		{"Godot_v4.3-stable_export_templates.tpz", "", "200", "2024-08-15T12:00:00Z"},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records but got: %v", len(expected), records)
+	}
+
+	for index := range expected {
+		for field := range expected[index] {
+			if records[index][field] != expected[index][field] {
+				t.Errorf("expected %v but got %v", expected[index], records[index])
+				break
+			}
+		}
+	}
+}
+
+type summary struct {
+	Total    int64            `json:"total" yaml:"total"`
+	Archives []map[string]any `json:"archives" yaml:"archives"`
+}
+
+func TestRenderJson(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := Render(JSON, &buffer, newTable())
+	if err != nil {
+		t.Fatalf("cannot render json: %s", err)
+	}
+
+	var result summary
+	err = json.Unmarshal(buffer.Bytes(), &result)
+	if err != nil {
+		t.Fatalf("cannot parse json: %s", err)
+	}
+
+	if result.Total != 300 || len(result.Archives) != 2 {
+		t.Fatalf("expected total and archives but got: %s", buffer.String())
+	}
+
+	platforms, ok := result.Archives[1]["platforms"].([]any)
+	if !ok || len(platforms) != 0 {
+		t.Errorf("expected an empty platforms list but got: %v", result.Archives[1]["platforms"])
+	}
+
+	if result.Archives[0]["downloaded"] != "2024-08-15T12:00:00Z" {
+		t.Errorf("expected an rfc 3339 time but got: %v", result.Archives[0]["downloaded"])
+	}
+}
+
+func TestRenderYaml(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := Render(YAML, &buffer, newTable())
+	if err != nil {
+		t.Fatalf("cannot render yaml: %s", err)
+	}
+
+	var result summary
+	err = yaml.Unmarshal(buffer.Bytes(), &result)
+	if err != nil {
+		t.Fatalf("cannot parse yaml: %s", err)
+	}
+
+	if result.Total != 300 || len(result.Archives) != 2 {
+		t.Fatalf("expected total and archives but got: %s", buffer.String())
+	}
+
+	if result.Archives[0]["size"] != 100 {
+		t.Errorf("expected size in bytes but got: %v", result.Archives[0]["size"])
+	}
+}
@@ -2,7 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func AtoiIfNotEmpty(value string) (int, error) {
@@ -17,3 +21,53 @@ func AtoiIfNotEmpty(value string) (int, error) {
 
 	return result, nil
 }
+
+const SIZE_REGEX_PATTERN = "^(?i)\\s*([0-9]+([.][0-9]+)?)\\s*([kmgtp]?)(i?b)?\\s*$"
+const DURATION_REGEX_PATTERN = "^\\s*([0-9]+)\\s*([dw])\\s*$"
+
+var SizeRegex = regexp.MustCompile(SIZE_REGEX_PATTERN)
+var DurationRegex = regexp.MustCompile(DURATION_REGEX_PATTERN)
+
+func ParseBytes(value string) (int64, error) {
+	parts := SizeRegex.FindStringSubmatch(value)
+	if parts == nil {
+		return 0, fmt.Errorf("invalid size: %s", value)
+	}
+
+	number, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("conversion failed: %w", err)
+	}
+
+	exponent := 0
+	if len(parts[3]) > 0 {
+		exponent = strings.Index("kmgtp", strings.ToLower(parts[3])) + 1
+	}
+
+	return int64(number * math.Pow(1024, float64(exponent))), nil
+}
+
+func ParseDuration(value string) (time.Duration, error) {
+	parts := DurationRegex.FindStringSubmatch(value)
+	if parts == nil {
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %w", err)
+		}
+
+		return duration, nil
+	}
+
+	number, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("conversion failed: %w", err)
+	}
+
+	day := 24 * time.Hour
+	switch parts[2] {
+	case "w":
+		return time.Duration(number) * 7 * day, nil
+	default:
+		return time.Duration(number) * day, nil
+	}
+}
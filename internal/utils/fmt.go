@@ -7,3 +7,22 @@ import (
 func Printlnf(format string, a ...any) (n int, err error) {
 	return fmt.Println(fmt.Sprintf(format, a...))
 }
+
+func FormatBytes(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value := float64(bytes)
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+
+	var unit string
+	for _, unit = range units {
+		value /= 1024
+		if value < 1024 {
+			break
+		}
+	}
+
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
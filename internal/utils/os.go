@@ -1,16 +1,22 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/bashmills/gevm/filesystem"
 )
 
-func LocateExecutable(callback func(string) bool, root string, isDir bool) (string, error) {
+func LocateExecutable(filesystem filesystem.Filesystem, callback func(string) bool, root string, isDir bool) (string, error) {
 	var result string
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+	err := filesystem.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("could not walk path: %w", err)
 		}
@@ -31,14 +37,14 @@ func LocateExecutable(callback func(string) bool, root string, isDir bool) (stri
 	}
 
 	if len(result) == 0 {
-		return "", fmt.Errorf("executable not found")
+		return "", fmt.Errorf("executable not found: %w", os.ErrNotExist)
 	}
 
 	return result, nil
 }
 
-func IsDirectoryEmpty(path string) (bool, error) {
-	entries, err := os.ReadDir(path)
+func IsDirectoryEmpty(filesystem filesystem.Filesystem, path string) (bool, error) {
+	entries, err := filesystem.ReadDir(path)
 	if err != nil {
 		return false, fmt.Errorf("cannot read directory: %w", err)
 	}
@@ -46,8 +52,107 @@ func IsDirectoryEmpty(path string) (bool, error) {
 	return len(entries) == 0, nil
 }
 
-func DoesExist(path string) (bool, error) {
-	_, err := os.Stat(path)
+func ChecksumFile(filesystem filesystem.Filesystem, path string) (string, error) {
+	file, err := filesystem.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("cannot hash file: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func CopyFile(filesystem filesystem.Filesystem, from string, to string) error {
+	src, err := filesystem.Open(from)
+	if err != nil {
+		return fmt.Errorf("cannot open source file: %w", err)
+	}
+	defer src.Close()
+
+	err = filesystem.MkdirAll(filepath.Dir(to), OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	dst, err := filesystem.CreateTemp(filepath.Dir(to), ".copy-*")
+	if err != nil {
+		return fmt.Errorf("cannot create temp file: %w", err)
+	}
+	defer filesystem.Remove(dst.Name())
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		return fmt.Errorf("cannot copy file: %w", err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close temp file: %w", err)
+	}
+
+	err = filesystem.Chmod(dst.Name(), OS_FILE)
+	if err != nil {
+		return fmt.Errorf("cannot set file permissions: %w", err)
+	}
+
+	err = filesystem.Rename(dst.Name(), to)
+	if err != nil {
+		return fmt.Errorf("cannot move file: %w", err)
+	}
+
+	return nil
+}
+
+func WriteFileAtomic(filesystem filesystem.Filesystem, path string, data []byte) error {
+	err := filesystem.MkdirAll(filepath.Dir(path), OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	file, err := filesystem.CreateTemp(filepath.Dir(path), ".write-*")
+	if err != nil {
+		return fmt.Errorf("cannot create temp file: %w", err)
+	}
+	defer filesystem.Remove(file.Name())
+	defer file.Close()
+
+	_, err = file.Write(data)
+	if err != nil {
+		return fmt.Errorf("cannot write temp file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close temp file: %w", err)
+	}
+
+	err = filesystem.Chmod(file.Name(), OS_FILE)
+	if err != nil {
+		return fmt.Errorf("cannot set file permissions: %w", err)
+	}
+
+	err = filesystem.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("cannot move file: %w", err)
+	}
+
+	return nil
+}
+
+func IsHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+func DoesExist(filesystem filesystem.Filesystem, path string) (bool, error) {
+	_, err := filesystem.Stat(path)
 	if errors.Is(err, os.ErrNotExist) {
 		return false, nil
 	}
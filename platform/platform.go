@@ -0,0 +1,92 @@
+package platform
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+type Platform string
+
+const (
+	ExportTemplates Platform = "Export Templates"
+	WindowsArm64    Platform = "Windows Arm64"
+	WindowsAmd64    Platform = "Windows Amd64"
+	WindowsX86      Platform = "Windows X86"
+	DarwinArm64     Platform = "Darwin Arm64"
+	DarwinAmd64     Platform = "Darwin Amd64"
+	LinuxArm64      Platform = "Linux Arm64"
+	LinuxArm32      Platform = "Linux Arm32"
+	LinuxAmd64      Platform = "Linux Amd64"
+	LinuxX86        Platform = "Linux X86"
+)
+
+var Platforms = []Platform{
+	ExportTemplates,
+	WindowsArm64,
+	WindowsAmd64,
+	WindowsX86,
+	DarwinArm64,
+	DarwinAmd64,
+	LinuxArm64,
+	LinuxArm32,
+	LinuxAmd64,
+	LinuxX86,
+}
+
+var Targets = map[string]Platform{
+	"windows/arm64": WindowsArm64,
+	"windows/amd64": WindowsAmd64,
+	"windows/386":   WindowsX86,
+	"darwin/arm64":  DarwinArm64,
+	"darwin/amd64":  DarwinAmd64,
+	"linux/arm64":   LinuxArm64,
+	"linux/arm":     LinuxArm32,
+	"linux/amd64":   LinuxAmd64,
+	"linux/386":     LinuxX86,
+}
+
+func (p Platform) Slug() string {
+	return strings.ReplaceAll(strings.ToLower(string(p)), " ", "-")
+}
+
+func (p Platform) IsEngine() bool {
+	return p != ExportTemplates
+}
+
+func Get() (Platform, error) {
+	platform, ok := Targets[runtime.GOOS+"/"+runtime.GOARCH]
+	if !ok {
+		return "", fmt.Errorf("invalid platform")
+	}
+
+	return platform, nil
+}
+
+func Parse(value string) (Platform, error) {
+	for _, platform := range Platforms {
+		if strings.EqualFold(value, string(platform)) || strings.EqualFold(value, platform.Slug()) {
+			return platform, nil
+		}
+	}
+
+	platform, ok := Targets[strings.ToLower(value)]
+	if !ok {
+		return "", fmt.Errorf("invalid platform: %s", value)
+	}
+
+	return platform, nil
+}
+
+func ParseEngine(value string) (Platform, error) {
+	platform, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	if !platform.IsEngine() {
+		return "", fmt.Errorf("invalid engine platform: %s", value)
+	}
+
+	return platform, nil
+}
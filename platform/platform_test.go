@@ -0,0 +1,35 @@
+package platform
+
+import "testing"
+
+func TestParseEngine(t *testing.T) {
+	tests := []struct {
+		Value    string
+		Expected Platform
+		Valid    bool
+	}{
+		{Value: "Windows Amd64", Expected: WindowsAmd64, Valid: true},
+		{Value: "linux/amd64", Expected: LinuxAmd64, Valid: true},
+		{Value: "darwin-arm64", Expected: DarwinArm64, Valid: true},
+		{Value: "Export Templates", Valid: false},
+		{Value: "export-templates", Valid: false},
+		{Value: "plan9/amd64", Valid: false},
+	}
+
+	for _, test := range tests {
+		result, err := ParseEngine(test.Value)
+		if (err == nil) != test.Valid {
+			t.Errorf("expected valid %t for '%s' but got: %v", test.Valid, test.Value, err)
+			continue
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected '%s' for '%s' but got '%s'", test.Expected, test.Value, result)
+		}
+	}
+
+	_, err := Parse("Export Templates")
+	if err != nil {
+		t.Errorf("expected export templates to parse: %s", err)
+	}
+}
@@ -1,13 +1,19 @@
 package repository
 
 import (
-	"github.com/bashmills/gevm/internal/platform"
+	"time"
+
+	"github.com/bashmills/gevm/platform"
 	"github.com/bashmills/gevm/semver"
 )
 
 type Download struct {
-	Assets map[platform.Platform]Asset
-	Relver semver.Relver
+	Assets     map[platform.Platform]Asset
+	Relver     semver.Relver
+	Published  time.Time
+	Notes      string
+	URL        string
+	Prerelease bool
 }
 
 func (d Download) HasAsset(platform platform.Platform) bool {
@@ -17,6 +23,7 @@ func (d Download) HasAsset(platform platform.Platform) bool {
 type Asset struct {
 	DownloadURL string
 	Name        string
+	Size        int64
 }
 
 func (a Asset) IsValid() bool {
@@ -0,0 +1,127 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var Operators = []string{
+	">=",
+	"<=",
+	"!=",
+	">",
+	"<",
+	"=",
+	"~",
+	"^",
+}
+
+type comparator struct {
+	Operator string
+	Parts    []int
+}
+
+func (c comparator) matches(version Version) bool {
+	parts := []int{version.Major, version.Minor, version.Patch, version.Build}
+	result := compareParts(parts, c.Parts)
+
+	switch c.Operator {
+	case ">=":
+		return result >= 0
+	case "<=":
+		return result <= 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case "<":
+		return result < 0
+	case "~":
+		return result >= 0 && compareParts(parts, c.Parts[:min(len(c.Parts), 2)]) == 0
+	case "^":
+		return result >= 0 && compareParts(parts, c.Parts[:1]) == 0
+	default:
+		return result == 0
+	}
+}
+
+type Constraint struct {
+	Original    string
+	comparators []comparator
+}
+
+func (c Constraint) Matches(version Version) bool {
+	for _, comparator := range c.comparators {
+		if !comparator.matches(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Constraint) String() string {
+	return c.Original
+}
+
+func ParseConstraint(constraint string) (Constraint, error) {
+	var comparators []comparator
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			return Constraint{}, fmt.Errorf("empty comparator: %s", constraint)
+		}
+
+		operator := "="
+		for _, candidate := range Operators {
+			if strings.HasPrefix(part, candidate) {
+				operator = candidate
+				part = strings.TrimSpace(strings.TrimPrefix(part, candidate))
+				break
+			}
+		}
+
+		var parts []int
+		for _, field := range strings.Split(part, ".") {
+			if field == "x" || field == "*" {
+				break
+			}
+
+			value, err := strconv.Atoi(field)
+			if err != nil || value < 0 {
+				return Constraint{}, fmt.Errorf("invalid constraint version: %s", part)
+			}
+
+			parts = append(parts, value)
+		}
+
+		if len(parts) == 0 || len(parts) > 4 {
+			return Constraint{}, fmt.Errorf("invalid constraint version: %s", part)
+		}
+
+		comparators = append(comparators, comparator{
+			Operator: operator,
+			Parts:    parts,
+		})
+	}
+
+	return Constraint{
+		Original:    constraint,
+		comparators: comparators,
+	}, nil
+}
+
+func compareParts(parts []int, other []int) int {
+	for i, value := range other {
+		if parts[i] < value {
+			return -1
+		}
+
+		if parts[i] > value {
+			return 1
+		}
+	}
+
+	return 0
+}
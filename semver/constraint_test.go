@@ -0,0 +1,79 @@
+package semver
+
+import "testing"
+
+func TestConstraintMatches(t *testing.T) {
+	tests := []struct {
+		Constraint string
+		Version    string
+		Expected   bool
+	}{
+		{Constraint: "4.3", Version: "4.3", Expected: true},
+		{Constraint: "4.3", Version: "4.3.1", Expected: true},
+		{Constraint: "4.3", Version: "4.4", Expected: false},
+		{Constraint: "=4.3.1", Version: "4.3.1", Expected: true},
+		{Constraint: "=4.3.1", Version: "4.3.2", Expected: false},
+		{Constraint: "4", Version: "4.2.2", Expected: true},
+		{Constraint: "4.x", Version: "4.2.2", Expected: true},
+		{Constraint: "4.*", Version: "3.6", Expected: false},
+		{Constraint: "!=4.3", Version: "4.3.1", Expected: false},
+		{Constraint: "!=4.3", Version: "4.2", Expected: true},
+		{Constraint: ">4.2", Version: "4.2.2", Expected: false},
+		{Constraint: ">4.2", Version: "4.3", Expected: true},
+		{Constraint: "<4.2", Version: "4.1.4", Expected: true},
+		{Constraint: "<4.2", Version: "4.2", Expected: false},
+		{Constraint: "~4.2", Version: "4.2.2", Expected: true},
+		{Constraint: "~4.2", Version: "4.3", Expected: false},
+		{Constraint: "~4.2.1", Version: "4.2", Expected: false},
+		{Constraint: "^4.2", Version: "4.3", Expected: true},
+		{Constraint: "^4.2", Version: "4.1", Expected: false},
+		{Constraint: "^4.2", Version: "5.0", Expected: false},
+		{Constraint: ">=4.2,<4.4", Version: "4.3.1", Expected: true},
+		{Constraint: ">=4.2,<4.4", Version: "4.4", Expected: false},
+		{Constraint: ">=4.2,<4.4", Version: "4.1", Expected: false},
+		{Constraint: ">=3,<5,!=4.0", Version: "4.0.1", Expected: false},
+		{Constraint: " >= 4.2 , < 4.4 ", Version: "4.3", Expected: true},
+		{Constraint: "\t~ 4.2", Version: "4.2.4", Expected: true},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.Constraint)
+		if err != nil {
+			t.Errorf("cannot parse constraint '%s': %s", test.Constraint, err)
+			continue
+		}
+
+		version, err := ParseVersion(test.Version)
+		if err != nil {
+			t.Fatalf("cannot parse version '%s': %s", test.Version, err)
+		}
+
+		result := constraint.Matches(version)
+		if result != test.Expected {
+			t.Errorf("expected %t for '%s' against '%s' but got %t", test.Expected, test.Version, test.Constraint, result)
+		}
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		" ",
+		">=4.2,",
+		",<4.4",
+		">=",
+		"x",
+		"four",
+		"4.a",
+		"4.-1",
+		"=>4.2",
+		"1.2.3.4.5",
+	}
+
+	for _, test := range tests {
+		_, err := ParseConstraint(test)
+		if err == nil {
+			t.Errorf("expected '%s' to be invalid", test)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/bundling"
+	"github.com/bashmills/gevm/internal/caching"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/environment/github"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/exporttemplates"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+type Archive struct {
+	Name       string              `json:"name"`
+	Path       string              `json:"path"`
+	Folder     string              `json:"folder"`
+	Semver     semver.Semver       `json:"-"`
+	Platforms  []platform.Platform `json:"platforms"`
+	Size       int64               `json:"size"`
+	Downloaded time.Time           `json:"downloaded"`
+	Installed  bool                `json:"installed"`
+}
+
+type Policy struct {
+	MaxSize       int64
+	OlderThan     time.Duration
+	KeepInstalled bool
+	KeepLatest    int
+}
+
+func (p Policy) IsEmpty() bool {
+	return p.MaxSize <= 0 && p.OlderThan <= 0 && p.KeepLatest <= 0
+}
+
+type IndexFetcher interface {
+	FetchIndex(ctx context.Context, relver semver.Relver) (string, error)
+}
+
+type Service struct {
+	Environment *environment.Environment
+	Config      *config.Config
+}
+
+func (s *Service) Clear() error {
+	s.Config.Logger.Debug("Attempting to clear cache directory: %s", s.Config.CacheDirectory)
+
+	err := s.Config.Filesystem.RemoveAll(s.Config.CacheDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot remove cache directory: %w", err)
+	}
+
+	s.Config.Logger.Info("Cache cleared")
+	return nil
+}
+
+func (s *Service) Prune(policy Policy) error {
+	s.Config.Logger.Debug("Attempting to prune cache directory: %s", s.Config.CacheDirectory)
+
+	if policy.IsEmpty() {
+		s.Config.Logger.Info("No prune policy specified")
+		return nil
+	}
+
+	archives, err := s.Archives()
+	if err != nil {
+		return fmt.Errorf("cannot read cached archives: %w", err)
+	}
+
+	protected := map[string]bool{}
+	remove := map[string]bool{}
+
+	if policy.KeepInstalled {
+		for _, archive := range archives {
+			if archive.Installed {
+				protected[archive.Path] = true
+			}
+		}
+	}
+
+	if policy.KeepLatest > 0 {
+		groups := map[string][]Archive{}
+		for _, archive := range archives {
+			key := fmt.Sprintf("%s|%v|%t|%d.%d", archive.Folder, archive.Platforms, archive.Semver.Mono, archive.Semver.Relver.Version.Major, archive.Semver.Relver.Version.Minor)
+			groups[key] = append(groups[key], archive)
+		}
+
+		for _, group := range groups {
+			slices.SortFunc(group, func(a Archive, b Archive) int { return b.Semver.Compare(a.Semver) })
+			for index, archive := range group {
+				if index < policy.KeepLatest {
+					protected[archive.Path] = true
+				} else {
+					remove[archive.Path] = true
+				}
+			}
+		}
+	}
+
+	if policy.OlderThan > 0 {
+		threshold := time.Now().Add(-policy.OlderThan)
+		for _, archive := range archives {
+			if archive.Downloaded.Before(threshold) {
+				remove[archive.Path] = true
+			}
+		}
+	}
+
+	if policy.MaxSize > 0 {
+		var total int64
+		var candidates []Archive
+		for _, archive := range archives {
+			if remove[archive.Path] && !protected[archive.Path] {
+				continue
+			}
+
+			total += archive.Size
+			if !protected[archive.Path] {
+				candidates = append(candidates, archive)
+			}
+		}
+
+		slices.SortFunc(candidates, func(a Archive, b Archive) int { return a.Downloaded.Compare(b.Downloaded) })
+
+		for _, archive := range candidates {
+			if total <= policy.MaxSize {
+				break
+			}
+
+			remove[archive.Path] = true
+			total -= archive.Size
+		}
+
+		if total > policy.MaxSize {
+			s.Config.Logger.Warning("Cache still exceeds maximum size after pruning: %s", utils.FormatBytes(total))
+		}
+	}
+
+	var count int
+	var freed int64
+	for _, archive := range archives {
+		if !remove[archive.Path] || protected[archive.Path] {
+			continue
+		}
+
+		archiveLock, locked, err := caching.TryLock(s.Config, archive.Folder, archive.Name)
+		if err != nil {
+			return fmt.Errorf("cannot lock archive: %w", err)
+		}
+
+		if !locked {
+			s.Config.Logger.Debug("Skipping archive in use: %s", archive.Path)
+			continue
+		}
+
+		s.Config.Logger.Debug("Removing archive: %s", archive.Path)
+
+		err = s.Config.Filesystem.Remove(archive.Path)
+		archiveLock.Release()
+		if err != nil {
+			return fmt.Errorf("cannot remove archive: %w", err)
+		}
+
+		count++
+		freed += archive.Size
+	}
+
+	s.Config.Logger.Info("Cache pruned: removed %d archives (%s)", count, utils.FormatBytes(freed))
+	return nil
+}
+
+func (s *Service) AutoPrune() error {
+	if !s.Config.AutoPrune {
+		return nil
+	}
+
+	policy, err := s.ConfigPolicy()
+	if err != nil {
+		return fmt.Errorf("cannot create prune policy: %w", err)
+	}
+
+	err = s.Prune(policy)
+	if err != nil {
+		return fmt.Errorf("cannot prune cache: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) ConfigPolicy() (Policy, error) {
+	policy := Policy{
+		KeepInstalled: s.Config.PruneKeepInstalled,
+		KeepLatest:    s.Config.PruneKeepLatest,
+	}
+
+	if len(s.Config.PruneMaxSize) > 0 {
+		maxSize, err := utils.ParseBytes(s.Config.PruneMaxSize)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid max size: %w", err)
+		}
+
+		policy.MaxSize = maxSize
+	}
+
+	if len(s.Config.PruneOlderThan) > 0 {
+		olderThan, err := utils.ParseDuration(s.Config.PruneOlderThan)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid older than: %w", err)
+		}
+
+		policy.OlderThan = olderThan
+	}
+
+	return policy, nil
+}
+
+func (s *Service) Export(ctx context.Context, bundlePath string, semvers []semver.Semver) error {
+	s.Config.Logger.Debug("Attempting to export cache bundle: %s", bundlePath)
+
+	archives, err := s.Archives()
+	if err != nil {
+		return fmt.Errorf("cannot read cached archives: %w", err)
+	}
+
+	var paths []string
+	for _, semver := range semvers {
+		found := false
+		for _, archive := range archives {
+			if !archive.Semver.Equal(semver) || archive.Semver.Mono != semver.Mono {
+				continue
+			}
+
+			s.Config.Logger.Debug("Adding archive: %s", archive.Path)
+			paths = append(paths, archive.Path)
+			found = true
+		}
+
+		if !found {
+			return fmt.Errorf("%w: no cached archives for '%s'", errs.ErrNotInstalled, semver.GodotString())
+		}
+
+		indexPath, err := s.indexPath(ctx, semver.Relver)
+		if err != nil {
+			return fmt.Errorf("cannot locate release index: %w", err)
+		}
+
+		if !slices.Contains(paths, indexPath) {
+			s.Config.Logger.Debug("Adding release index: %s", indexPath)
+			paths = append(paths, indexPath)
+		}
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("nothing to export")
+	}
+
+	err = bundling.Write(ctx, s.Config.Filesystem, bundlePath, s.Config.CacheDirectory, paths)
+	if err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+
+	s.Config.Logger.Info("Cache bundle '%s' exported", filepath.Base(bundlePath))
+	return nil
+}
+
+func (s *Service) Import(ctx context.Context, bundlePath string) error {
+	s.Config.Logger.Debug("Attempting to import cache bundle: %s", bundlePath)
+
+	entries, err := bundling.Read(ctx, s.Config.Filesystem, bundlePath, s.Config.CacheDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot read bundle: %w", err)
+	}
+
+	for _, entry := range entries {
+		s.Config.Logger.Debug("Imported: %s", entry.Path)
+	}
+
+	s.Config.Logger.Info("Cache bundle '%s' imported", filepath.Base(bundlePath))
+	return nil
+}
+
+func (s *Service) indexPath(ctx context.Context, relver semver.Relver) (string, error) {
+	indexPath := github.IndexPath(s.Config, relver)
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, indexPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if exists {
+		return indexPath, nil
+	}
+
+	for _, fetcher := range s.Environment.Fetchers {
+		indexFetcher, ok := fetcher.(IndexFetcher)
+		if !ok {
+			continue
+		}
+
+		s.Config.Logger.Debug("Fetching release index for '%s'", relver.GodotString())
+
+		return indexFetcher.FetchIndex(ctx, relver)
+	}
+
+	return "", fmt.Errorf("%w: no release index for '%s'", errs.ErrVersionNotFound, relver.GodotString())
+}
+
+func (s *Service) Archives() ([]Archive, error) {
+	return s.ArchivesIn(s.Config.CacheDirectory)
+}
+
+func (s *Service) ArchivesIn(root string) ([]Archive, error) {
+	var archives []Archive
+	for _, folder := range []string{godot.CACHE_FOLDER, exporttemplates.CACHE_FOLDER} {
+		directory := filepath.Join(root, folder)
+		entries, err := s.Config.Filesystem.ReadDir(directory)
+		if !errors.Is(err, os.ErrNotExist) && err != nil {
+			return nil, fmt.Errorf("cannot read cache directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || utils.IsHidden(entry.Name()) {
+				continue
+			}
+
+			semver, err := semver.Parse(entry.Name())
+			if err != nil {
+				s.Config.Logger.Warning("Failed to recognize version: %s", err)
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("cannot read archive info: %w", err)
+			}
+
+			var platforms []platform.Platform
+			var installedDirectory string
+
+			switch folder {
+			case godot.CACHE_FOLDER:
+				platforms = github.AssetPlatforms(entry.Name())
+				if slices.Contains(platforms, s.Config.Platform) {
+					installedDirectory = filepath.Join(s.Config.GodotRootDirectory, semver.GodotString())
+				}
+			case exporttemplates.CACHE_FOLDER:
+				platforms = []platform.Platform{platform.ExportTemplates}
+				installedDirectory = filepath.Join(s.Config.ExportTemplatesRootDirectory, semver.ExportTemplatesString())
+			}
+
+			installed := false
+			if len(installedDirectory) > 0 {
+				installed, err = utils.DoesExist(s.Config.Filesystem, installedDirectory)
+				if err != nil {
+					s.Config.Logger.Warning("Failed to check installation existence: %s", err)
+				}
+			}
+
+			archives = append(archives, Archive{
+				Name:       entry.Name(),
+				Path:       filepath.Join(directory, entry.Name()),
+				Folder:     folder,
+				Semver:     semver,
+				Platforms:  platforms,
+				Size:       info.Size(),
+				Downloaded: info.ModTime(),
+				Installed:  installed,
+			})
+		}
+	}
+
+	return archives, nil
+}
+
+func New(environment *environment.Environment, config *config.Config) *Service {
+	return &Service{
+		Environment: environment,
+		Config:      config,
+	}
+}
@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/environment/github"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/exporttemplates"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+func TestArchivesInstalled(t *testing.T) {
+	root := t.TempDir()
+
+	config := fixtures.NewConfig(t, root)
+
+	err := os.MkdirAll(filepath.Join(config.GodotRootDirectory, "4.3-stable"), 0755)
+	if err != nil {
+		t.Fatalf("cannot make installation: %s", err)
+	}
+
+	expected := map[string]bool{
+		"Godot_v4.3-stable_linux.x86_64.zip": true,
+		"Godot_v4.3-stable_win64.exe.zip":    false,
+	}
+
+	for name := range expected {
+		path := filepath.Join(config.CacheDirectory, godot.CACHE_FOLDER, name)
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("cannot make cache folder: %s", err)
+		}
+
+		err = os.WriteFile(path, []byte("archive"), 0644)
+		if err != nil {
+			t.Fatalf("cannot write archive: %s", err)
+		}
+	}
+
+	archives, err := New(nil, config).Archives()
+	if err != nil {
+		t.Fatalf("cannot list archives: %s", err)
+	}
+
+	if len(archives) != len(expected) {
+		t.Fatalf("expected %d archives but got %d", len(expected), len(archives))
+	}
+
+	for _, archive := range archives {
+		if archive.Installed != expected[archive.Name] {
+			t.Errorf("expected installed %t for '%s' but got %t", expected[archive.Name], archive.Name, archive.Installed)
+		}
+	}
+}
+
+func TestArchivesInventory(t *testing.T) {
+	config := fixtures.NewConfig(t, t.TempDir())
+
+	files := map[string]string{
+		filepath.Join(godot.CACHE_FOLDER, "Godot_v4.3-stable_mono_linux_x86_64.zip"):                    "engine",
+		filepath.Join(godot.CACHE_FOLDER, ".Godot_v4.3-stable_linux.x86_64.zip.lock"):                   "",
+		filepath.Join(godot.CACHE_FOLDER, "notes.txt"):                                                  "notes",
+		filepath.Join(exporttemplates.CACHE_FOLDER, "Godot_v4.3-stable_export_templates.tpz"):           "templates",
+		filepath.Join(exporttemplates.CACHE_FOLDER, "nested", "Godot_v4.2-stable_export_templates.tpz"): "templates",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(config.CacheDirectory, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("cannot make cache folder: %s", err)
+		}
+
+		err = os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("cannot write file: %s", err)
+		}
+	}
+
+	err := os.MkdirAll(filepath.Join(config.ExportTemplatesRootDirectory, "4.3.stable"), 0755)
+	if err != nil {
+		t.Fatalf("cannot make installation: %s", err)
+	}
+
+	archives, err := New(nil, config).Archives()
+	if err != nil {
+		t.Fatalf("cannot list archives: %s", err)
+	}
+
+	if len(archives) != 2 {
+		t.Fatalf("expected two archives but got: %v", archives)
+	}
+
+	engine := archives[0]
+	if engine.Folder != godot.CACHE_FOLDER || engine.Size != 6 || !engine.Semver.Mono || engine.Installed {
+		t.Errorf("unexpected engine archive: %v", engine)
+	}
+
+	if !slices.Equal(engine.Platforms, []platform.Platform{platform.LinuxAmd64}) {
+		t.Errorf("expected linux platform but got: %v", engine.Platforms)
+	}
+
+	templates := archives[1]
+	if templates.Folder != exporttemplates.CACHE_FOLDER || templates.Size != 9 || !templates.Installed {
+		t.Errorf("unexpected export templates archive: %v", templates)
+	}
+
+	if !slices.Equal(templates.Platforms, []platform.Platform{platform.ExportTemplates}) {
+		t.Errorf("expected export templates platform but got: %v", templates.Platforms)
+	}
+}
+
+type indexFetcher struct {
+	fixtures.Fetcher
+	Config *config.Config
+}
+
+func (f *indexFetcher) FetchIndex(ctx context.Context, relver semver.Relver) (string, error) {
+	indexPath := github.IndexPath(f.Config, relver)
+
+	err := os.MkdirAll(filepath.Dir(indexPath), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return indexPath, os.WriteFile(indexPath, []byte("{}"), 0644)
+}
+
+func TestExport(t *testing.T) {
+	root := t.TempDir()
+	config := fixtures.NewConfig(t, root)
+	bundlePath := filepath.Join(root, "bundle.tar")
+
+	archivePath := filepath.Join(config.CacheDirectory, godot.CACHE_FOLDER, "Godot_v4.3-stable_linux.x86_64.zip")
+	err := os.MkdirAll(filepath.Dir(archivePath), 0755)
+	if err != nil {
+		t.Fatalf("cannot make cache folder: %s", err)
+	}
+
+	err = os.WriteFile(archivePath, []byte("archive"), 0644)
+	if err != nil {
+		t.Fatalf("cannot write archive: %s", err)
+	}
+
+	environment, err := environment.New([]fetcher.Fetcher{&fixtures.Fetcher{}}, config)
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	service := New(environment, config)
+	ctx := context.Background()
+
+	err = service.Export(ctx, bundlePath, []semver.Semver{semver.Maybe("4.2", "stable", false)})
+	if !errors.Is(err, errs.ErrNotInstalled) {
+		t.Errorf("expected not installed for an uncached version but got: %v", err)
+	}
+
+	err = service.Export(ctx, bundlePath, []semver.Semver{semver.Maybe("4.3", "stable", false)})
+	if !errors.Is(err, errs.ErrVersionNotFound) {
+		t.Errorf("expected version not found without a release index but got: %v", err)
+	}
+
+	_, err = os.Stat(bundlePath)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no bundle to be written after a failed export")
+	}
+
+	environment.Fetchers = []fetcher.Fetcher{&indexFetcher{Config: config}}
+
+	err = service.Export(ctx, bundlePath, []semver.Semver{semver.Maybe("4.3", "stable", false)})
+	if err != nil {
+		t.Fatalf("cannot export: %s", err)
+	}
+
+	_, err = os.Stat(bundlePath)
+	if err != nil {
+		t.Errorf("expected bundle to be written: %s", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	ages := map[string]time.Duration{
+		"Godot_v4.2-stable_linux.x86_64.zip":   10 * 24 * time.Hour,
+		"Godot_v4.2.2-stable_linux.x86_64.zip": 5 * 24 * time.Hour,
+		"Godot_v4.3-stable_linux.x86_64.zip":   time.Hour,
+	}
+
+	tests := []struct {
+		Name      string
+		Policy    Policy
+		Installed string
+		Expected  []string
+	}{
+		{
+			Name:     "keep latest",
+			Policy:   Policy{KeepLatest: 1},
+			Expected: []string{"Godot_v4.2.2-stable_linux.x86_64.zip", "Godot_v4.3-stable_linux.x86_64.zip"},
+		},
+		{
+			Name:     "older than",
+			Policy:   Policy{OlderThan: 6 * 24 * time.Hour},
+			Expected: []string{"Godot_v4.2.2-stable_linux.x86_64.zip", "Godot_v4.3-stable_linux.x86_64.zip"},
+		},
+		{
+			Name:     "max size",
+			Policy:   Policy{MaxSize: 150},
+			Expected: []string{"Godot_v4.3-stable_linux.x86_64.zip"},
+		},
+		{
+			Name:      "max size keep installed",
+			Policy:    Policy{MaxSize: 150, KeepInstalled: true},
+			Installed: "4.2-stable",
+			Expected:  []string{"Godot_v4.2-stable_linux.x86_64.zip"},
+		},
+		{
+			Name:     "empty",
+			Policy:   Policy{},
+			Expected: []string{"Godot_v4.2-stable_linux.x86_64.zip", "Godot_v4.2.2-stable_linux.x86_64.zip", "Godot_v4.3-stable_linux.x86_64.zip"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			config := fixtures.NewConfig(t, t.TempDir())
+
+			for name, age := range ages {
+				path := filepath.Join(config.CacheDirectory, godot.CACHE_FOLDER, name)
+				err := os.MkdirAll(filepath.Dir(path), 0755)
+				if err != nil {
+					t.Fatalf("cannot make cache folder: %s", err)
+				}
+
+				err = os.WriteFile(path, make([]byte, 100), 0644)
+				if err != nil {
+					t.Fatalf("cannot write archive: %s", err)
+				}
+
+				modified := time.Now().Add(-age)
+				err = os.Chtimes(path, modified, modified)
+				if err != nil {
+					t.Fatalf("cannot age archive: %s", err)
+				}
+			}
+
+			if len(test.Installed) > 0 {
+				err := os.MkdirAll(filepath.Join(config.GodotRootDirectory, test.Installed), 0755)
+				if err != nil {
+					t.Fatalf("cannot make installation: %s", err)
+				}
+			}
+
+			service := New(nil, config)
+
+			err := service.Prune(test.Policy)
+			if err != nil {
+				t.Fatalf("cannot prune: %s", err)
+			}
+
+			archives, err := service.Archives()
+			if err != nil {
+				t.Fatalf("cannot list archives: %s", err)
+			}
+
+			var names []string
+			for _, archive := range archives {
+				names = append(names, archive.Name)
+			}
+
+			slices.Sort(names)
+			if !slices.Equal(names, test.Expected) {
+				t.Errorf("expected %v to remain but got %v", test.Expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package exporttemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/archiving"
+	"github.com/bashmills/gevm/internal/caching"
+	"github.com/bashmills/gevm/internal/downloading"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/locking"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+)
+
+const CACHE_FOLDER = "export-templates"
+const TEMP_FOLDER = "templates"
+const VERSION_FILENAME = "version.txt"
+const STAGING_PATTERN = ".staging-*"
+const PLATFORMS_FILENAME = ".platforms"
+
+var ErrInvalidPlatform = errors.New("invalid platform")
+
+var Platforms = map[string][]string{
+	"android": {"android"},
+	"ios":     {"ios", "iphone"},
+	"linux":   {"linux", "x11"},
+	"macos":   {"macos", "osx"},
+	"uwp":     {"uwp"},
+	"web":     {"web", "javascript"},
+	"windows": {"windows"},
+}
+
+type CachePruner interface {
+	AutoPrune() error
+}
+
+type Installation struct {
+	Semver    semver.Semver
+	Directory string
+	Platforms []string
+}
+
+type Service struct {
+	Environment *environment.Environment
+	CachePruner CachePruner
+	Config      *config.Config
+}
+
+func (s *Service) Download(ctx context.Context, semver semver.Semver) error {
+	s.Config.Logger.Debug("Attempting to download '%s' export templates...", semver.ExportTemplatesString())
+
+	asset, err := s.Environment.FetchExportTemplatesAsset(ctx, semver)
+	if err != nil {
+		return fmt.Errorf("fetch asset failed: %w", err)
+	}
+
+	archiveLock, err := caching.Lock(ctx, s.Config, CACHE_FOLDER, asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot lock archive: %w", err)
+	}
+	defer archiveLock.Release()
+
+	archivePath, err := s.archivePath(asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot locate archive: %w", err)
+	}
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if exists {
+		s.Config.Logger.Info("Export templates '%s' already downloaded", semver.ExportTemplatesString())
+		return nil
+	}
+
+	s.Config.Logger.Debug("Downloading from: %s", asset.DownloadURL)
+	s.Config.Logger.Debug("Downloading to: %s", archivePath)
+
+	err = downloading.Download(ctx, s.Config, asset.DownloadURL, archivePath)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return &errs.AssetNotFoundError{
+			Platform: string(platform.ExportTemplates),
+			Version:  semver.ExportTemplatesString(),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	s.autoPrune()
+
+	s.Config.Logger.Info("Export templates '%s' downloaded", semver.ExportTemplatesString())
+	return nil
+}
+
+func (s *Service) Uninstall(ctx context.Context, semver semver.Semver) error {
+	s.Config.Logger.Debug("Attempting to uninstall '%s' export templates...", semver.ExportTemplatesString())
+
+	targetDirectory := s.targetDirectory(semver)
+
+	targetLock, err := locking.Acquire(ctx, s.Config, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot lock target directory: %w", err)
+	}
+	defer targetLock.Release()
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %s", errs.ErrNotInstalled, semver.ExportTemplatesString())
+	}
+
+	s.Config.Logger.Debug("Removing directory: %s", targetDirectory)
+
+	err = s.Config.Filesystem.RemoveAll(targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot remove target directory: %w", err)
+	}
+
+	s.Config.Logger.Info("Export templates '%s' uninstalled", semver.ExportTemplatesString())
+	return nil
+}
+
+func (s *Service) Install(ctx context.Context, semver semver.Semver, platforms []string) error {
+	s.Config.Logger.Debug("Attempting to install '%s' export templates...", semver.ExportTemplatesString())
+
+	platforms, err := s.selectPlatforms(platforms)
+	if err != nil {
+		return fmt.Errorf("cannot select platforms: %w", err)
+	}
+
+	asset, err := s.Environment.FetchExportTemplatesAsset(ctx, semver)
+	if err != nil {
+		return fmt.Errorf("fetch asset failed: %w", err)
+	}
+
+	targetDirectory := s.targetDirectory(semver)
+
+	targetLock, err := locking.Acquire(ctx, s.Config, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot lock target directory: %w", err)
+	}
+	defer targetLock.Release()
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	selected := platforms
+	var existing []string
+
+	if exists {
+		var partial bool
+		existing, partial, err = s.partialPlatforms(semver)
+		if err != nil {
+			return fmt.Errorf("cannot read installed platforms: %w", err)
+		}
+
+		if !partial {
+			return fmt.Errorf("%w: %s", errs.ErrAlreadyInstalled, semver.ExportTemplatesString())
+		}
+
+		requested := platforms
+		if len(requested) == 0 {
+			requested = platformNames()
+		}
+
+		selected = nil
+		for _, platform := range requested {
+			if !slices.Contains(existing, platform) {
+				selected = append(selected, platform)
+			}
+		}
+
+		if len(selected) == 0 {
+			return fmt.Errorf("%w: %s", errs.ErrAlreadyInstalled, semver.ExportTemplatesString())
+		}
+
+		s.Config.Logger.Info("Adding platforms to installed export templates: %s", strings.Join(selected, ", "))
+	}
+
+	err = s.Config.Filesystem.MkdirAll(s.Config.ExportTemplatesRootDirectory, utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	archiveLock, err := caching.Lock(ctx, s.Config, CACHE_FOLDER, asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot lock archive: %w", err)
+	}
+	defer archiveLock.Release()
+
+	archivePath, err := s.archivePath(asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot locate archive: %w", err)
+	}
+
+	s.Config.Logger.Debug("Downloading from: %s", asset.DownloadURL)
+	s.Config.Logger.Debug("Downloading to: %s", archivePath)
+
+	err = downloading.Download(ctx, s.Config, asset.DownloadURL, archivePath)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return &errs.AssetNotFoundError{
+			Platform: string(platform.ExportTemplates),
+			Version:  semver.ExportTemplatesString(),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	stagingDirectory, err := s.Config.Filesystem.MkdirTemp(s.Config.ExportTemplatesRootDirectory, STAGING_PATTERN)
+	if err != nil {
+		return fmt.Errorf("cannot make staging directory: %w", err)
+	}
+	defer s.Config.Filesystem.RemoveAll(stagingDirectory)
+
+	s.Config.Logger.Debug("Extracting from: %s", archivePath)
+	s.Config.Logger.Debug("Extracting to: %s", stagingDirectory)
+
+	if len(selected) > 0 {
+		s.Config.Logger.Debug("Only extracting platforms: %s", strings.Join(selected, ", "))
+	}
+
+	err = archiving.Extract(ctx, s.Config, archivePath, stagingDirectory, func(name string) bool {
+		platform := platformOf(filepath.Base(name))
+		return len(selected) == 0 || len(platform) == 0 || slices.Contains(selected, platform)
+	})
+	if err != nil {
+		return fmt.Errorf("extract failed: %w", err)
+	}
+
+	tempDirectory := filepath.Join(stagingDirectory, TEMP_FOLDER)
+
+	err = s.validate(semver, tempDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot validate install: %w", err)
+	}
+
+	if exists {
+		err = s.addPlatforms(semver, tempDirectory, selected, existing, len(platforms) == 0)
+		if err != nil {
+			return fmt.Errorf("cannot add platforms: %w", err)
+		}
+	} else {
+		if len(platforms) > 0 {
+			err = s.Config.Filesystem.WriteFile(filepath.Join(tempDirectory, PLATFORMS_FILENAME), []byte(strings.Join(platforms, "\n")), utils.OS_FILE)
+			if err != nil {
+				return fmt.Errorf("cannot write installed platforms: %w", err)
+			}
+		}
+
+		err = s.Config.Filesystem.Chmod(tempDirectory, utils.OS_DIRECTORY)
+		if err != nil {
+			return fmt.Errorf("cannot set directory permissions: %w", err)
+		}
+
+		s.Config.Logger.Debug("Moving from: %s", tempDirectory)
+		s.Config.Logger.Debug("Moving to: %s", targetDirectory)
+
+		err = s.Config.Filesystem.Rename(tempDirectory, targetDirectory)
+		if err != nil {
+			return fmt.Errorf("move failed: %w", err)
+		}
+	}
+
+	s.autoPrune()
+
+	s.Config.Logger.Info("Export templates '%s' installed", semver.ExportTemplatesString())
+	return nil
+}
+
+func (s *Service) ListInstalled() ([]Installation, error) {
+	entries, err := s.Config.Filesystem.ReadDir(s.Config.ExportTemplatesRootDirectory)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return nil, fmt.Errorf("cannot read export templates root directory: %w", err)
+	}
+
+	var installations []Installation
+	for _, entry := range entries {
+		if !entry.IsDir() || utils.IsHidden(entry.Name()) {
+			continue
+		}
+
+		semver, err := semver.Parse(entry.Name())
+		if err != nil {
+			s.Config.Logger.Warning("Failed to recognize version: %s", err)
+			continue
+		}
+
+		platforms, err := s.installedPlatforms(semver)
+		if err != nil {
+			s.Config.Logger.Warning("Failed to determine installed platforms: %s", err)
+		}
+
+		installations = append(installations, Installation{
+			Semver:    semver,
+			Directory: filepath.Join(s.Config.ExportTemplatesRootDirectory, entry.Name()),
+			Platforms: platforms,
+		})
+	}
+
+	return installations, nil
+}
+
+func (s *Service) Clear(ctx context.Context) error {
+	entries, err := s.Config.Filesystem.ReadDir(s.Config.ExportTemplatesRootDirectory)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return fmt.Errorf("cannot read export templates root directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || utils.IsHidden(entry.Name()) {
+			continue
+		}
+
+		semver, err := semver.Parse(entry.Name())
+		if err != nil {
+			s.Config.Logger.Warning("Failed to recognize version: %s", err)
+			continue
+		}
+
+		err = s.Uninstall(ctx, semver)
+		if err != nil {
+			return fmt.Errorf("cannot clear export templates: %w", err)
+		}
+	}
+
+	s.Config.Logger.Info("Export templates cleared")
+	return nil
+}
+
+func (s *Service) Exists(semver semver.Semver) (bool, error) {
+	targetDirectory := s.targetDirectory(semver)
+	exists, err := utils.DoesExist(s.Config.Filesystem, targetDirectory)
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (s *Service) installedPlatforms(semver semver.Semver) ([]string, error) {
+	entries, err := s.Config.Filesystem.ReadDir(s.targetDirectory(semver))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read target directory: %w", err)
+	}
+
+	var platforms []string
+	for _, entry := range entries {
+		platform := platformOf(entry.Name())
+		if len(platform) == 0 || slices.Contains(platforms, platform) {
+			continue
+		}
+
+		platforms = append(platforms, platform)
+	}
+
+	slices.Sort(platforms)
+
+	return platforms, nil
+}
+
+// partialPlatforms reads the platforms of an install made with only some
+// platforms selected. Installs of every platform have no platforms file.
+func (s *Service) partialPlatforms(semver semver.Semver) ([]string, bool, error) {
+	bytes, err := s.Config.Filesystem.ReadFile(filepath.Join(s.targetDirectory(semver), PLATFORMS_FILENAME))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot read platforms file: %w", err)
+	}
+
+	return strings.Fields(string(bytes)), true, nil
+}
+
+func (s *Service) addPlatforms(semver semver.Semver, directory string, selected []string, existing []string, complete bool) error {
+	targetDirectory := s.targetDirectory(semver)
+
+	entries, err := s.Config.Filesystem.ReadDir(directory)
+	if err != nil {
+		return fmt.Errorf("cannot read extracted directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !slices.Contains(selected, platformOf(entry.Name())) {
+			continue
+		}
+
+		s.Config.Logger.Debug("Moving to: %s", filepath.Join(targetDirectory, entry.Name()))
+
+		err = s.Config.Filesystem.Rename(filepath.Join(directory, entry.Name()), filepath.Join(targetDirectory, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("move failed: %w", err)
+		}
+	}
+
+	platformsPath := filepath.Join(targetDirectory, PLATFORMS_FILENAME)
+	if complete {
+		return s.Config.Filesystem.Remove(platformsPath)
+	}
+
+	platforms := append(slices.Clone(existing), selected...)
+	slices.Sort(platforms)
+
+	return utils.WriteFileAtomic(s.Config.Filesystem, platformsPath, []byte(strings.Join(platforms, "\n")))
+}
+
+func (s *Service) selectPlatforms(platforms []string) ([]string, error) {
+	if len(platforms) == 0 {
+		platforms = strings.Split(s.Config.ExportTemplatesPlatforms, ",")
+	}
+
+	var result []string
+	for _, platform := range platforms {
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		if len(platform) == 0 {
+			continue
+		}
+
+		if _, ok := Platforms[platform]; !ok {
+			return nil, fmt.Errorf("%w: %s (expected one of: %s)", ErrInvalidPlatform, platform, strings.Join(platformNames(), ", "))
+		}
+
+		result = append(result, platform)
+	}
+
+	return result, nil
+}
+
+func (s *Service) autoPrune() {
+	err := s.CachePruner.AutoPrune()
+	if err != nil {
+		s.Config.Logger.Warning("Failed to prune cache: %s", err)
+	}
+}
+
+func (s *Service) targetDirectory(semver semver.Semver) string {
+	return filepath.Join(s.Config.ExportTemplatesRootDirectory, semver.ExportTemplatesString())
+}
+
+func (s *Service) archivePath(name string) (string, error) {
+	return caching.Locate(s.Config, CACHE_FOLDER, name)
+}
+
+func (s *Service) validate(expected semver.Semver, directory string) error {
+	bytes, err := s.Config.Filesystem.ReadFile(filepath.Join(directory, VERSION_FILENAME))
+	if err != nil {
+		return fmt.Errorf("cannot read version file: %w", err)
+	}
+
+	actual, err := semver.Parse(strings.TrimSpace(string(bytes)))
+	if err != nil {
+		return fmt.Errorf("cannot parse version file: %w", err)
+	}
+
+	if !actual.Equal(expected) || actual.Mono != expected.Mono {
+		return fmt.Errorf("version mismatch: %s != %s", actual.ExportTemplatesString(), expected.ExportTemplatesString())
+	}
+
+	return nil
+}
+
+func platformNames() []string {
+	var names []string
+	for platform := range Platforms {
+		names = append(names, platform)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func platformOf(filename string) string {
+	filename = strings.ToLower(filename)
+	for platform, prefixes := range Platforms {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(filename, prefix) {
+				return platform
+			}
+		}
+	}
+
+	return ""
+}
+
+func New(environment *environment.Environment, cachePruner CachePruner, config *config.Config) *Service {
+	return &Service{
+		Environment: environment,
+		CachePruner: cachePruner,
+		Config:      config,
+	}
+}
@@ -0,0 +1,111 @@
+package exporttemplates
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+)
+
+const ARCHIVE_NAME = "Godot_v4.3-stable_export_templates.tpz"
+
+type fakePruner struct{}
+
+func (fakePruner) AutoPrune() error {
+	return nil
+}
+
+func newArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	for name, content := range map[string]string{
+		"templates/version.txt":                "4.3.stable",
+		"templates/linux_debug.x86_64":         "linux",
+		"templates/web_debug.zip":              "web",
+		"templates/windows_debug_x86_64.exe":   "windows",
+		"templates/windows_release_x86_64.exe": "windows",
+	} {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("cannot create archive entry: %s", err)
+		}
+
+		_, err = file.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("cannot write archive entry: %s", err)
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close archive: %s", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestInstallAddsPlatforms(t *testing.T) {
+	server := fixtures.NewServer(t, map[string][]byte{"/" + ARCHIVE_NAME: newArchive(t)})
+	download := fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.ExportTemplates: server.URL + "/" + ARCHIVE_NAME})
+	config := fixtures.NewConfig(t, t.TempDir())
+
+	environment, err := environment.New([]fetcher.Fetcher{&fixtures.Fetcher{Downloads: []repository.Download{download}}}, config)
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	service := New(environment, fakePruner{}, config)
+	version := semver.Maybe("4.3", "stable", false)
+
+	tests := []struct {
+		Only     []string
+		Expected []string
+		Already  bool
+	}{
+		{Only: []string{"linux"}, Expected: []string{"linux"}},
+		{Only: []string{"linux"}, Expected: []string{"linux"}, Already: true},
+		{Only: []string{"web", "linux"}, Expected: []string{"linux", "web"}},
+		{Expected: []string{"linux", "web", "windows"}},
+		{Expected: []string{"linux", "web", "windows"}, Already: true},
+		{Only: []string{"android"}, Expected: []string{"linux", "web", "windows"}, Already: true},
+	}
+
+	for index, test := range tests {
+		err := service.Install(context.Background(), version, test.Only)
+		if errors.Is(err, errs.ErrAlreadyInstalled) != test.Already {
+			t.Fatalf("install %d: expected already installed %t but got: %v", index, test.Already, err)
+		}
+		if err != nil && !test.Already {
+			t.Fatalf("install %d: cannot install: %s", index, err)
+		}
+
+		platforms, err := service.installedPlatforms(version)
+		if err != nil {
+			t.Fatalf("install %d: cannot read platforms: %s", index, err)
+		}
+
+		if !slices.Equal(platforms, test.Expected) {
+			t.Errorf("install %d: expected platforms %v but got %v", index, test.Expected, platforms)
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(service.targetDirectory(version), PLATFORMS_FILENAME))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected platforms file to be removed once every platform is installed")
+	}
+}
@@ -0,0 +1,351 @@
+package godot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/archiving"
+	"github.com/bashmills/gevm/internal/caching"
+	"github.com/bashmills/gevm/internal/downloading"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/locking"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+)
+
+const CACHE_FOLDER = "godot"
+const STAGING_PATTERN = ".staging-*"
+
+type ExportTemplatesChecker interface {
+	Exists(semver semver.Semver) (bool, error)
+}
+
+type ExecutableLocator interface {
+	Find(semver semver.Semver, platform platform.Platform, console bool) (string, error)
+	Locate(root string, platform platform.Platform, console bool) (string, error)
+}
+
+type CachePruner interface {
+	AutoPrune() error
+}
+
+type Installation struct {
+	Semver          semver.Semver
+	Directory       string
+	ExportTemplates bool
+}
+
+type Service struct {
+	Environment            *environment.Environment
+	ExportTemplatesChecker ExportTemplatesChecker
+	ExecutableLocator      ExecutableLocator
+	CachePruner            CachePruner
+	Config                 *config.Config
+}
+
+func (s *Service) Download(ctx context.Context, semver semver.Semver, platform platform.Platform) error {
+	s.Config.Logger.Debug("Attempting to download '%s' godot for platform: %s", semver.GodotString(), platform)
+
+	if !platform.IsEngine() {
+		return fmt.Errorf("invalid engine platform: %s", platform)
+	}
+
+	asset, err := s.Environment.FetchGodotAsset(ctx, platform, semver)
+	if err != nil {
+		return fmt.Errorf("fetch asset failed: %w", err)
+	}
+
+	archiveLock, err := caching.Lock(ctx, s.Config, CACHE_FOLDER, asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot lock archive: %w", err)
+	}
+	defer archiveLock.Release()
+
+	archivePath, err := s.archivePath(asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot locate archive: %w", err)
+	}
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if exists {
+		s.Config.Logger.Info("Godot '%s' already downloaded", semver.GodotString())
+		return nil
+	}
+
+	s.Config.Logger.Debug("Downloading from: %s", asset.DownloadURL)
+	s.Config.Logger.Debug("Downloading to: %s", archivePath)
+
+	err = downloading.Download(ctx, s.Config, asset.DownloadURL, archivePath)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return &errs.AssetNotFoundError{
+			Platform: string(platform),
+			Version:  semver.GodotString(),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	s.autoPrune()
+
+	s.Config.Logger.Info("Godot '%s' downloaded", semver.GodotString())
+	return nil
+}
+
+func (s *Service) Uninstall(ctx context.Context, semver semver.Semver) error {
+	s.Config.Logger.Debug("Attempting to uninstall '%s' godot...", semver.GodotString())
+
+	targetDirectory := s.targetDirectory(semver)
+
+	targetLock, err := locking.Acquire(ctx, s.Config, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot lock target directory: %w", err)
+	}
+	defer targetLock.Release()
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %s", errs.ErrNotInstalled, semver.GodotString())
+	}
+
+	s.Config.Logger.Debug("Removing directory: %s", targetDirectory)
+
+	err = s.Config.Filesystem.RemoveAll(targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot remove target directory: %w", err)
+	}
+
+	s.Config.Logger.Info("Godot '%s' uninstalled", semver.GodotString())
+	return nil
+}
+
+func (s *Service) Install(ctx context.Context, semver semver.Semver) error {
+	s.Config.Logger.Debug("Attempting to install '%s' godot...", semver.GodotString())
+
+	asset, err := s.Environment.FetchGodotAsset(ctx, s.Config.Platform, semver)
+	if err != nil {
+		return fmt.Errorf("fetch asset failed: %w", err)
+	}
+
+	targetDirectory := s.targetDirectory(semver)
+
+	targetLock, err := locking.Acquire(ctx, s.Config, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("cannot lock target directory: %w", err)
+	}
+	defer targetLock.Release()
+
+	exists, err := utils.DoesExist(s.Config.Filesystem, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	if exists {
+		return fmt.Errorf("%w: %s", errs.ErrAlreadyInstalled, semver.GodotString())
+	}
+
+	err = s.Config.Filesystem.MkdirAll(s.Config.GodotRootDirectory, utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot make directory: %w", err)
+	}
+
+	archiveLock, err := caching.Lock(ctx, s.Config, CACHE_FOLDER, asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot lock archive: %w", err)
+	}
+	defer archiveLock.Release()
+
+	archivePath, err := s.archivePath(asset.Name)
+	if err != nil {
+		return fmt.Errorf("cannot locate archive: %w", err)
+	}
+
+	s.Config.Logger.Debug("Downloading from: %s", asset.DownloadURL)
+	s.Config.Logger.Debug("Downloading to: %s", archivePath)
+
+	err = downloading.Download(ctx, s.Config, asset.DownloadURL, archivePath)
+	if errors.Is(err, downloading.ErrNotFound) {
+		return &errs.AssetNotFoundError{
+			Platform: string(s.Config.Platform),
+			Version:  semver.GodotString(),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	stagingDirectory, err := s.Config.Filesystem.MkdirTemp(s.Config.GodotRootDirectory, STAGING_PATTERN)
+	if err != nil {
+		return fmt.Errorf("cannot make staging directory: %w", err)
+	}
+	defer s.Config.Filesystem.RemoveAll(stagingDirectory)
+
+	s.Config.Logger.Debug("Extracting from: %s", archivePath)
+	s.Config.Logger.Debug("Extracting to: %s", stagingDirectory)
+
+	err = archiving.Extract(ctx, s.Config, archivePath, stagingDirectory, nil)
+	if err != nil {
+		return fmt.Errorf("extract failed: %w", err)
+	}
+
+	executablePath, err := s.ExecutableLocator.Locate(stagingDirectory, s.Config.Platform, false)
+	if err != nil {
+		return fmt.Errorf("cannot validate install: %w", err)
+	}
+
+	s.Config.Logger.Trace("Executable found: %s", executablePath)
+
+	err = s.Config.Filesystem.Chmod(stagingDirectory, utils.OS_DIRECTORY)
+	if err != nil {
+		return fmt.Errorf("cannot set directory permissions: %w", err)
+	}
+
+	s.Config.Logger.Debug("Moving from: %s", stagingDirectory)
+	s.Config.Logger.Debug("Moving to: %s", targetDirectory)
+
+	err = s.Config.Filesystem.Rename(stagingDirectory, targetDirectory)
+	if err != nil {
+		return fmt.Errorf("move failed: %w", err)
+	}
+
+	s.autoPrune()
+
+	s.Config.Logger.Info("Godot '%s' installed", semver.GodotString())
+	return nil
+}
+
+func (s *Service) Path(semver semver.Semver, platform platform.Platform, console bool) (string, error) {
+	if !platform.IsEngine() {
+		return "", fmt.Errorf("invalid engine platform: %s", platform)
+	}
+
+	targetPath, err := s.ExecutableLocator.Find(semver, platform, console)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", errs.ErrNotInstalled, semver.GodotString())
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot determine target path: %w", err)
+	}
+
+	return targetPath, nil
+}
+
+func (s *Service) IsInstalled(semver semver.Semver) (bool, error) {
+	exists, err := utils.DoesExist(s.Config.Filesystem, s.targetDirectory(semver))
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (s *Service) ListInstalled() ([]Installation, error) {
+	entries, err := s.Config.Filesystem.ReadDir(s.Config.GodotRootDirectory)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return nil, fmt.Errorf("cannot read godot root directory: %w", err)
+	}
+
+	var installations []Installation
+	for _, entry := range entries {
+		if !entry.IsDir() || utils.IsHidden(entry.Name()) {
+			continue
+		}
+
+		semver, err := semver.Parse(entry.Name())
+		if err != nil {
+			s.Config.Logger.Warning("Failed to recognize version: %s", err)
+			continue
+		}
+
+		exportTemplates, err := s.ExportTemplatesChecker.Exists(semver)
+		if err != nil {
+			s.Config.Logger.Warning("Failed to check export templates existence: %s", err)
+		}
+
+		installations = append(installations, Installation{
+			Semver:          semver,
+			Directory:       filepath.Join(s.Config.GodotRootDirectory, entry.Name()),
+			ExportTemplates: exportTemplates,
+		})
+	}
+
+	return installations, nil
+}
+
+func (s *Service) Clear(ctx context.Context) error {
+	entries, err := s.Config.Filesystem.ReadDir(s.Config.GodotRootDirectory)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return fmt.Errorf("cannot read godot root directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || utils.IsHidden(entry.Name()) {
+			continue
+		}
+
+		semver, err := semver.Parse(entry.Name())
+		if err != nil {
+			s.Config.Logger.Warning("Failed to recognize version: %s", err)
+			continue
+		}
+
+		err = s.Uninstall(ctx, semver)
+		if err != nil {
+			return fmt.Errorf("cannot clear godot: %w", err)
+		}
+	}
+
+	empty, err := utils.IsDirectoryEmpty(s.Config.Filesystem, s.Config.GodotRootDirectory)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return fmt.Errorf("failed to check emptiness: %w", err)
+	}
+
+	if empty {
+		err = s.Config.Filesystem.Remove(s.Config.GodotRootDirectory)
+		if err != nil {
+			return fmt.Errorf("cannot remove godot root directory: %w", err)
+		}
+	}
+
+	s.Config.Logger.Info("Godot cleared")
+	return nil
+}
+
+func (s *Service) autoPrune() {
+	err := s.CachePruner.AutoPrune()
+	if err != nil {
+		s.Config.Logger.Warning("Failed to prune cache: %s", err)
+	}
+}
+
+func (s *Service) targetDirectory(semver semver.Semver) string {
+	return filepath.Join(s.Config.GodotRootDirectory, semver.GodotString())
+}
+
+func (s *Service) archivePath(name string) (string, error) {
+	return caching.Locate(s.Config, CACHE_FOLDER, name)
+}
+
+func New(environment *environment.Environment, exportTemplatesChecker ExportTemplatesChecker, executableLocator ExecutableLocator, cachePruner CachePruner, config *config.Config) *Service {
+	return &Service{
+		Environment:            environment,
+		ExportTemplatesChecker: exportTemplatesChecker,
+		ExecutableLocator:      executableLocator,
+		CachePruner:            cachePruner,
+		Config:                 config,
+	}
+}
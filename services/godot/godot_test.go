@@ -0,0 +1,200 @@
+package godot
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/filesystem"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/internal/locator"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+)
+
+const EXECUTABLE_NAME = "Godot_v4.3-stable_linux.x86_64"
+const ARCHIVE_NAME = "Godot_v4.3-stable_linux.x86_64.zip"
+
+type fakeChecker struct{}
+
+func (fakeChecker) Exists(semver semver.Semver) (bool, error) {
+	return false, nil
+}
+
+type fakePruner struct{}
+
+func (fakePruner) AutoPrune() error {
+	return nil
+}
+
+func newArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	header := &zip.FileHeader{Name: EXECUTABLE_NAME}
+	header.SetMode(0755)
+
+	file, err := writer.CreateHeader(header)
+	if err != nil {
+		t.Fatalf("cannot create archive entry: %s", err)
+	}
+
+	_, err = file.Write([]byte("godot"))
+	if err != nil {
+		t.Fatalf("cannot write archive entry: %s", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close archive: %s", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func newService(t *testing.T) (*Service, string) {
+	t.Helper()
+
+	server := fixtures.NewServer(t, map[string][]byte{"/" + ARCHIVE_NAME: newArchive(t)})
+	download := fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: server.URL + "/" + ARCHIVE_NAME})
+
+	parent := t.TempDir()
+	root := filepath.Join(parent, "sandbox")
+
+	sandbox, err := filesystem.NewSandbox(root)
+	if err != nil {
+		t.Fatalf("cannot create sandbox: %s", err)
+	}
+
+	config := fixtures.NewConfig(t, string(filepath.Separator))
+	config.Filesystem = sandbox
+
+	environment, err := environment.New([]fetcher.Fetcher{&fixtures.Fetcher{Downloads: []repository.Download{download}}}, config)
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	locator, err := locator.New(config)
+	if err != nil {
+		t.Fatalf("cannot create locator: %s", err)
+	}
+
+	return New(environment, fakeChecker{}, locator, fakePruner{}, config), parent
+}
+
+func TestInstallUninstallClear(t *testing.T) {
+	service, parent := newService(t)
+	ctx := context.Background()
+	version := semver.Maybe("4.3", "stable", false)
+
+	err := service.Install(ctx, version)
+	if err != nil {
+		t.Fatalf("cannot install: %s", err)
+	}
+
+	path, err := service.Path(version, platform.LinuxAmd64, false)
+	if err != nil {
+		t.Fatalf("cannot find path: %s", err)
+	}
+
+	expected := filepath.Join(string(filepath.Separator), "godot", "4.3-stable", EXECUTABLE_NAME)
+	if path != expected {
+		t.Errorf("expected path '%s' but got '%s'", expected, path)
+	}
+
+	_, err = os.Stat(filepath.Join(parent, "sandbox", "godot", "4.3-stable", EXECUTABLE_NAME))
+	if err != nil {
+		t.Errorf("executable not installed inside the sandbox: %s", err)
+	}
+
+	_, err = os.Stat(filepath.Join(parent, "sandbox", "cache", CACHE_FOLDER, ARCHIVE_NAME))
+	if err != nil {
+		t.Errorf("archive not cached inside the sandbox: %s", err)
+	}
+
+	err = service.Install(ctx, version)
+	if !errors.Is(err, errs.ErrAlreadyInstalled) {
+		t.Errorf("expected already installed but got: %v", err)
+	}
+
+	installations, err := service.ListInstalled()
+	if err != nil || len(installations) != 1 {
+		t.Errorf("expected one installation but got: %v %v", installations, err)
+	}
+
+	err = service.Uninstall(ctx, version)
+	if err != nil {
+		t.Fatalf("cannot uninstall: %s", err)
+	}
+
+	installed, err := service.IsInstalled(version)
+	if err != nil || installed {
+		t.Errorf("expected version to be uninstalled: %v", err)
+	}
+
+	err = service.Uninstall(ctx, version)
+	if !errors.Is(err, errs.ErrNotInstalled) {
+		t.Errorf("expected not installed but got: %v", err)
+	}
+
+	err = service.Install(ctx, version)
+	if err != nil {
+		t.Fatalf("cannot reinstall: %s", err)
+	}
+
+	err = service.Clear(ctx)
+	if err != nil {
+		t.Fatalf("cannot clear: %s", err)
+	}
+
+	installations, err = service.ListInstalled()
+	if err != nil || len(installations) != 0 {
+		t.Errorf("expected no installations but got: %v %v", installations, err)
+	}
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatalf("cannot read parent: %s", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name() != "sandbox" {
+			t.Errorf("file written outside the sandbox: %s", entry.Name())
+		}
+	}
+}
+
+func TestInstallPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not supported on windows")
+	}
+
+	service, parent := newService(t)
+	version := semver.Maybe("4.3", "stable", false)
+
+	err := service.Install(context.Background(), version)
+	if err != nil {
+		t.Fatalf("cannot install: %s", err)
+	}
+
+	info, err := os.Stat(filepath.Join(parent, "sandbox", "godot", "4.3-stable"))
+	if err != nil {
+		t.Fatalf("cannot stat install directory: %s", err)
+	}
+
+	if info.Mode().Perm() != utils.OS_DIRECTORY.Perm() {
+		t.Errorf("expected install directory mode %s but got %s", utils.OS_DIRECTORY.Perm(), info.Mode().Perm())
+	}
+}
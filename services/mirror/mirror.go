@@ -0,0 +1,608 @@
+package mirror
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/internal/downloading"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/environment/github"
+	"github.com/bashmills/gevm/internal/locking"
+	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/cache"
+	"github.com/bashmills/gevm/services/exporttemplates"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+const DEFAULT_PER_PAGE = 30
+const MAX_PER_PAGE = 100
+const SHUTDOWN_TIMEOUT = 10 * time.Second
+const READ_HEADER_TIMEOUT = 10 * time.Second
+const IDLE_TIMEOUT = 2 * time.Minute
+const DOWNLOAD_PATH = "/download/%s/%s"
+const INDEX_NAME = "index.json"
+const CHECKSUMS_NAME = "SHA256SUMS"
+
+type SyncOptions struct {
+	Constraints []semver.Constraint
+	All         bool
+	Standard    bool
+	Mono        bool
+	Platforms   []platform.Platform
+}
+
+type Entry struct {
+	Name      string              `json:"name"`
+	Folder    string              `json:"folder"`
+	Version   string              `json:"version"`
+	Release   string              `json:"release"`
+	Mono      bool                `json:"mono"`
+	Platforms []platform.Platform `json:"platforms"`
+	URL       string              `json:"url"`
+	Size      int64               `json:"size"`
+	Checksum  string              `json:"sha256"`
+	Synced    time.Time           `json:"synced"`
+	Fetched   bool                `json:"-"`
+}
+
+type Service struct {
+	Environment *environment.Environment
+	Cache       *cache.Service
+	Config      *config.Config
+}
+
+func (s *Service) Sync(ctx context.Context, directory string, options SyncOptions) ([]Entry, error) {
+	s.Config.Logger.Debug("Attempting to sync mirror directory: %s", directory)
+
+	indexPath := filepath.Join(directory, INDEX_NAME)
+
+	indexLock, err := locking.Acquire(ctx, s.Config, indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot lock mirror index: %w", err)
+	}
+	defer indexLock.Release()
+
+	existing, err := s.readIndex(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read mirror index: %w", err)
+	}
+
+	var flavours []bool
+	if options.Standard {
+		flavours = append(flavours, false)
+	}
+
+	if options.Mono {
+		flavours = append(flavours, true)
+	}
+
+	var entries []Entry
+	var fetched int
+	indexes := map[semver.Relver]*github.Data{}
+
+	for _, mono := range flavours {
+		downloads, err := s.Environment.FetchDownloads(ctx, mono)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch environment downloads: %w", err)
+		}
+
+		for _, download := range downloads {
+			if !download.Relver.IsStable() && !options.All {
+				continue
+			}
+
+			if !matchesAny(options.Constraints, download.Relver.Version) {
+				continue
+			}
+
+			for _, target := range options.Platforms {
+				asset, ok := download.Assets[target]
+				if !ok || !asset.IsValid() {
+					continue
+				}
+
+				index := slices.IndexFunc(entries, func(entry Entry) bool { return entry.Name == asset.Name })
+				if index >= 0 {
+					entries[index].Platforms = append(entries[index].Platforms, target)
+					continue
+				}
+
+				folder := godot.CACHE_FOLDER
+				if target == platform.ExportTemplates {
+					folder = exporttemplates.CACHE_FOLDER
+				}
+
+				entry, err := s.syncAsset(ctx, directory, folder, asset, existing)
+				if err != nil {
+					return nil, fmt.Errorf("cannot sync asset: %w", err)
+				}
+
+				entry.Version = download.Relver.Version.String()
+				entry.Release = download.Relver.Release.String()
+				entry.Mono = mono
+				entry.Platforms = []platform.Platform{target}
+				entries = append(entries, *entry)
+
+				data, exists := indexes[download.Relver]
+				if !exists {
+					data = &github.Data{
+						Name:        download.Relver.GodotString(),
+						PublishedAt: download.Published,
+						Body:        download.Notes,
+						HTMLURL:     download.URL,
+						Prerelease:  download.Prerelease,
+					}
+					indexes[download.Relver] = data
+				}
+
+				data.Assets = append(data.Assets, github.DataAsset{
+					DownloadURL: asset.DownloadURL,
+					Name:        asset.Name,
+					Size:        asset.Size,
+				})
+
+				if entry.Fetched {
+					fetched++
+				}
+			}
+		}
+	}
+
+	for _, entry := range existing {
+		if slices.ContainsFunc(entries, func(other Entry) bool { return other.Name == entry.Name }) {
+			continue
+		}
+
+		exists, err := utils.DoesExist(s.Config.Filesystem, filepath.Join(directory, entry.Folder, entry.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existence: %w", err)
+		}
+
+		if exists {
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a Entry, b Entry) int { return cmp.Compare(a.Folder+"/"+a.Name, b.Folder+"/"+b.Name) })
+
+	for relver, data := range indexes {
+		err = s.writeReleaseIndex(directory, relver, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot write release index: %w", err)
+		}
+	}
+
+	err = s.writeIndex(directory, entries)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write mirror index: %w", err)
+	}
+
+	s.Config.Logger.Info("Mirror synced: fetched %d of %d assets", fetched, len(entries))
+	return entries, nil
+}
+
+func (s *Service) syncAsset(ctx context.Context, directory string, folder string, asset repository.Asset, existing []Entry) (*Entry, error) {
+	path := filepath.Join(directory, folder, asset.Name)
+
+	info, err := s.Config.Filesystem.Stat(path)
+	if !errors.Is(err, os.ErrNotExist) && err != nil {
+		return nil, fmt.Errorf("cannot stat asset: %w", err)
+	}
+
+	if err == nil {
+		index := slices.IndexFunc(existing, func(entry Entry) bool { return entry.Folder == folder && entry.Name == asset.Name })
+		if index >= 0 && existing[index].Size == info.Size() && (asset.Size <= 0 || asset.Size == info.Size()) {
+			s.Config.Logger.Debug("Asset already synced: %s", asset.Name)
+			return &existing[index], nil
+		}
+
+		if index < 0 && asset.Size > 0 && asset.Size == info.Size() {
+			s.Config.Logger.Debug("Adopting unindexed asset: %s", asset.Name)
+			return s.newEntry(path, folder, asset, info.Size())
+		}
+
+		s.Config.Logger.Debug("Replacing mismatched asset: %s", asset.Name)
+
+		err = s.Config.Filesystem.Remove(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot remove mismatched asset: %w", err)
+		}
+	}
+
+	s.Config.Logger.Debug("Downloading from: %s", asset.DownloadURL)
+	s.Config.Logger.Debug("Downloading to: %s", path)
+
+	err = downloading.Download(ctx, s.Config, asset.DownloadURL, path)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+
+	info, err = s.Config.Filesystem.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat asset: %w", err)
+	}
+
+	return s.newEntry(path, folder, asset, info.Size())
+}
+
+func (s *Service) newEntry(path string, folder string, asset repository.Asset, size int64) (*Entry, error) {
+	checksum, err := utils.ChecksumFile(s.Config.Filesystem, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot checksum asset: %w", err)
+	}
+
+	return &Entry{
+		Name:     asset.Name,
+		Folder:   folder,
+		URL:      asset.DownloadURL,
+		Size:     size,
+		Checksum: checksum,
+		Synced:   time.Now(),
+		Fetched:  true,
+	}, nil
+}
+
+func (s *Service) readIndex(indexPath string) ([]Entry, error) {
+	bytes, err := s.Config.Filesystem.ReadFile(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read index: %w", err)
+	}
+
+	var entries []Entry
+	err = json.Unmarshal(bytes, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse index: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *Service) writeIndex(directory string, entries []Entry) error {
+	bytes, err := json.MarshalIndent(entries, "", "	")
+	if err != nil {
+		return fmt.Errorf("cannot encode index: %w", err)
+	}
+
+	var checksums strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&checksums, "%s  %s/%s\n", entry.Checksum, entry.Folder, entry.Name)
+	}
+
+	err = utils.WriteFileAtomic(s.Config.Filesystem, filepath.Join(directory, CHECKSUMS_NAME), []byte(checksums.String()))
+	if err != nil {
+		return fmt.Errorf("cannot write checksums: %w", err)
+	}
+
+	err = utils.WriteFileAtomic(s.Config.Filesystem, filepath.Join(directory, INDEX_NAME), bytes)
+	if err != nil {
+		return fmt.Errorf("cannot write index: %w", err)
+	}
+
+	return nil
+}
+
+// writeReleaseIndex stores release details in the same place as the cache
+// so a synced directory can also be served or used as a shared cache.
+func (s *Service) writeReleaseIndex(directory string, relver semver.Relver, data *github.Data) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cannot encode release index: %w", err)
+	}
+
+	err = utils.WriteFileAtomic(s.Config.Filesystem, filepath.Join(directory, github.IndexFolder(s.Config), github.IndexName(relver)), bytes)
+	if err != nil {
+		return fmt.Errorf("cannot write release index: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) releases(directory string, baseURL string) ([]github.Data, error) {
+	archives, err := s.Cache.ArchivesIn(directory)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cached archives: %w", err)
+	}
+
+	var relvers []semver.Relver
+	assets := map[semver.Relver][]github.DataAsset{}
+
+	for _, archive := range archives {
+		relver := archive.Semver.Relver
+		if _, exists := assets[relver]; !exists {
+			relvers = append(relvers, relver)
+		}
+
+		assets[relver] = append(assets[relver], github.DataAsset{
+			DownloadURL: strings.TrimSuffix(baseURL, "/") + fmt.Sprintf(DOWNLOAD_PATH, archive.Folder, url.PathEscape(archive.Name)),
+			Name:        archive.Name,
+			Size:        archive.Size,
+		})
+	}
+
+	slices.SortFunc(relvers, func(a semver.Relver, b semver.Relver) int { return b.Compare(a) })
+
+	var releases []github.Data
+	for _, relver := range relvers {
+		release := s.cachedRelease(directory, relver)
+		release.Name = relver.GodotString()
+		release.Prerelease = !relver.IsStable()
+		release.Assets = assets[relver]
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+func (s *Service) cachedRelease(directory string, relver semver.Relver) github.Data {
+	var data github.Data
+
+	bytes, err := s.Config.Filesystem.ReadFile(filepath.Join(directory, github.IndexFolder(s.Config), github.IndexName(relver)))
+	if err != nil {
+		return data
+	}
+
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		s.Config.Logger.Debug("Failed to parse release index: %s", err)
+		return github.Data{}
+	}
+
+	return data
+}
+
+func (s *Service) Serve(ctx context.Context, address string, baseURL string, directory string) error {
+	if len(directory) == 0 {
+		directory = s.Config.CacheDirectory
+	}
+
+	// No write timeout is set as serving a large archive over a slow link
+	// can legitimately take a long time.
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.handler(directory, baseURL),
+		ReadHeaderTimeout: READ_HEADER_TIMEOUT,
+		IdleTimeout:       IDLE_TIMEOUT,
+		BaseContext: func(_ net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	failures := make(chan error, 1)
+	go func() {
+		failures <- server.ListenAndServe()
+	}()
+
+	s.Config.Logger.Info("Serving mirror of '%s' on: %s", directory, address)
+
+	select {
+	case err := <-failures:
+		return fmt.Errorf("cannot serve mirror: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.Config.Logger.Debug("Attempting to shut down mirror...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		return fmt.Errorf("cannot shut down mirror: %w", err)
+	}
+
+	s.Config.Logger.Info("Mirror stopped")
+	return nil
+}
+
+func (s *Service) handler(directory string, baseURL string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /releases", func(w http.ResponseWriter, r *http.Request) {
+		s.handleReleases(w, r, directory, baseURL)
+	})
+	mux.HandleFunc("GET /releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		s.handleRelease(w, r, directory, baseURL)
+	})
+	mux.HandleFunc("GET /download/{folder}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.handleDownload(w, r, directory)
+	})
+
+	return mux
+}
+
+func (s *Service) handleReleases(w http.ResponseWriter, r *http.Request, directory string, baseURL string) {
+	s.Config.Logger.Debug("Serving releases: %s", r.URL)
+
+	page, err := queryInt(r, "page", 1)
+	if err != nil || page < 1 {
+		s.writeError(w, http.StatusBadRequest, "invalid page")
+		return
+	}
+
+	perPage, err := queryInt(r, "per_page", DEFAULT_PER_PAGE)
+	if err != nil || perPage < 1 {
+		s.writeError(w, http.StatusBadRequest, "invalid per_page")
+		return
+	}
+
+	perPage = min(perPage, MAX_PER_PAGE)
+
+	releases, err := s.releases(directory, requestURL(r, baseURL))
+	if err != nil {
+		s.Config.Logger.Error("Failed to list releases: %s", err)
+		s.writeError(w, http.StatusInternalServerError, "cannot list releases")
+		return
+	}
+
+	last := max((len(releases)+perPage-1)/perPage, 1)
+	start := min((page-1)*perPage, len(releases))
+	end := min(start+perPage, len(releases))
+
+	var links []string
+	link := func(page int, rel string) {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(perPage))
+		links = append(links, fmt.Sprintf("<%s%s?%s>; rel=\"%s\"", requestURL(r, baseURL), r.URL.Path, query.Encode(), rel))
+	}
+
+	if page > 1 {
+		link(min(page-1, last), "prev")
+	}
+
+	if page < last {
+		link(page+1, "next")
+		link(last, "last")
+	}
+
+	if page > 1 {
+		link(1, "first")
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	s.writeJSON(w, http.StatusOK, append([]github.Data{}, releases[start:end]...))
+}
+
+func (s *Service) handleRelease(w http.ResponseWriter, r *http.Request, directory string, baseURL string) {
+	s.Config.Logger.Debug("Serving release: %s", r.URL)
+
+	releases, err := s.releases(directory, requestURL(r, baseURL))
+	if err != nil {
+		s.Config.Logger.Error("Failed to list releases: %s", err)
+		s.writeError(w, http.StatusInternalServerError, "cannot list releases")
+		return
+	}
+
+	tag := r.PathValue("tag")
+	for _, release := range releases {
+		if release.Name == tag {
+			s.writeJSON(w, http.StatusOK, release)
+			return
+		}
+	}
+
+	s.writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Service) handleDownload(w http.ResponseWriter, r *http.Request, directory string) {
+	s.Config.Logger.Debug("Serving download: %s", r.URL)
+
+	folder := r.PathValue("folder")
+	if folder != godot.CACHE_FOLDER && folder != exporttemplates.CACHE_FOLDER {
+		s.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	name := r.PathValue("name")
+	if filepath.Base(name) != name || utils.IsHidden(name) {
+		s.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	file, err := s.Config.Filesystem.Open(filepath.Join(directory, folder, name))
+	if errors.Is(err, os.ErrNotExist) {
+		s.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if err != nil {
+		s.Config.Logger.Error("Failed to open archive: %s", err)
+		s.writeError(w, http.StatusInternalServerError, "cannot open archive")
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		s.Config.Logger.Error("Failed to read archive info: %s", err)
+		s.writeError(w, http.StatusInternalServerError, "cannot read archive")
+		return
+	}
+
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
+
+func (s *Service) writeJSON(w http.ResponseWriter, status int, value any) {
+	bytes, err := json.MarshalIndent(value, "", "	")
+	if err != nil {
+		s.Config.Logger.Error("Failed to encode response: %s", err)
+		http.Error(w, "cannot encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(bytes)
+	if err != nil {
+		s.Config.Logger.Debug("Failed to write response: %s", err)
+	}
+}
+
+func (s *Service) writeError(w http.ResponseWriter, status int, message string) {
+	s.writeJSON(w, status, map[string]string{"message": message})
+}
+
+func matchesAny(constraints []semver.Constraint, version semver.Version) bool {
+	if len(constraints) == 0 {
+		return true
+	}
+
+	for _, constraint := range constraints {
+		if constraint.Matches(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func queryInt(r *http.Request, key string, fallback int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if len(value) == 0 {
+		return fallback, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func requestURL(r *http.Request, baseURL string) string {
+	if len(baseURL) > 0 {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
+
+func New(environment *environment.Environment, cache *cache.Service, config *config.Config) *Service {
+	return &Service{
+		Environment: environment,
+		Cache:       cache,
+		Config:      config,
+	}
+}
@@ -0,0 +1,209 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bashmills/gevm/fetcher"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/internal/environment/github"
+	"github.com/bashmills/gevm/internal/fixtures"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/services/cache"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+const ARCHIVE_NAME = "Godot_v4.3-stable_linux.x86_64.zip"
+const ARCHIVE_CONTENT = "engine"
+
+func newService(t *testing.T) *Service {
+	t.Helper()
+
+	server := fixtures.NewServer(t, map[string][]byte{"/" + ARCHIVE_NAME: []byte(ARCHIVE_CONTENT)})
+
+	download := fixtures.NewDownload("4.3-stable", map[platform.Platform]string{platform.LinuxAmd64: server.URL + "/" + ARCHIVE_NAME})
+	download.Notes = "Release notes"
+	download.Published = time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC)
+
+	config := fixtures.NewConfig(t, t.TempDir())
+
+	environment, err := environment.New([]fetcher.Fetcher{&fixtures.Fetcher{Downloads: []repository.Download{download}}}, config)
+	if err != nil {
+		t.Fatalf("cannot create environment: %s", err)
+	}
+
+	return New(environment, cache.New(environment, config), config)
+}
+
+func sync(t *testing.T, service *Service, directory string) []Entry {
+	t.Helper()
+
+	entries, err := service.Sync(context.Background(), directory, SyncOptions{
+		Standard:  true,
+		Platforms: []platform.Platform{platform.LinuxAmd64},
+	})
+	if err != nil {
+		t.Fatalf("cannot sync: %s", err)
+	}
+
+	return entries
+}
+
+func TestSyncServe(t *testing.T) {
+	service := newService(t)
+	directory := t.TempDir()
+
+	entries := sync(t, service, directory)
+	if len(entries) != 1 || !entries[0].Fetched {
+		t.Fatalf("expected one fetched entry but got: %v", entries)
+	}
+
+	releases, err := service.releases(directory, "http://mirror")
+	if err != nil {
+		t.Fatalf("cannot list releases: %s", err)
+	}
+
+	if len(releases) != 1 || releases[0].Name != "4.3-stable" || releases[0].Body != "Release notes" {
+		t.Fatalf("expected synced release with notes but got: %v", releases)
+	}
+
+	expected := "http://mirror/download/" + godot.CACHE_FOLDER + "/" + ARCHIVE_NAME
+	if len(releases[0].Assets) != 1 || releases[0].Assets[0].DownloadURL != expected {
+		t.Errorf("expected asset served from '%s' but got: %v", expected, releases[0].Assets)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/download/"+godot.CACHE_FOLDER+"/"+ARCHIVE_NAME, nil)
+	request.SetPathValue("folder", godot.CACHE_FOLDER)
+	request.SetPathValue("name", ARCHIVE_NAME)
+
+	recorder := httptest.NewRecorder()
+	service.handleDownload(recorder, request, directory)
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != ARCHIVE_CONTENT {
+		t.Errorf("expected synced archive to be served but got %d: %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestSyncMismatch(t *testing.T) {
+	service := newService(t)
+	directory := t.TempDir()
+
+	sync(t, service, directory)
+
+	path := filepath.Join(directory, godot.CACHE_FOLDER, ARCHIVE_NAME)
+	err := os.WriteFile(path, []byte("partial"), 0644)
+	if err != nil {
+		t.Fatalf("cannot corrupt archive: %s", err)
+	}
+
+	entries := sync(t, service, directory)
+	if len(entries) != 1 || !entries[0].Fetched {
+		t.Fatalf("expected mismatched archive to be fetched again but got: %v", entries)
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil || string(bytes) != ARCHIVE_CONTENT {
+		t.Errorf("expected archive to be replaced but got: %s %v", bytes, err)
+	}
+
+	entries = sync(t, service, directory)
+	if len(entries) != 1 || entries[0].Fetched {
+		t.Errorf("expected synced archive to be reused but got: %v", entries)
+	}
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("cannot request '%s': %s", url, err)
+	}
+	defer response.Body.Close()
+
+	bytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("cannot read response: %s", err)
+	}
+
+	return response, string(bytes)
+}
+
+func TestServe(t *testing.T) {
+	service := newService(t)
+	directory := service.Config.CacheDirectory
+
+	for _, name := range []string{"Godot_v4.2-stable_linux.x86_64.zip", "Godot_v4.3-stable_linux.x86_64.zip", "Godot_v4.4-dev1_linux.x86_64.zip"} {
+		path := filepath.Join(directory, godot.CACHE_FOLDER, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("cannot make cache folder: %s", err)
+		}
+
+		err = os.WriteFile(path, []byte(name), 0644)
+		if err != nil {
+			t.Fatalf("cannot write archive: %s", err)
+		}
+	}
+
+	server := httptest.NewServer(service.handler(directory, ""))
+	t.Cleanup(server.Close)
+
+	config := fixtures.NewConfig(t, t.TempDir())
+	config.SourceURL = server.URL
+
+	downloads, err := github.New(config).FetchDownloads(context.Background(), false)
+	if err != nil {
+		t.Fatalf("cannot fetch downloads from mirror: %s", err)
+	}
+
+	if len(downloads) != 3 {
+		t.Fatalf("expected three downloads but got: %v", downloads)
+	}
+
+	for _, download := range downloads {
+		asset := download.Assets[platform.LinuxAmd64]
+		if !strings.HasPrefix(asset.DownloadURL, server.URL) {
+			t.Errorf("expected asset served by the mirror but got: %s", asset.DownloadURL)
+			continue
+		}
+
+		response, body := get(t, asset.DownloadURL)
+		if response.StatusCode != http.StatusOK || body != asset.Name {
+			t.Errorf("expected '%s' to be served but got %d: %s", asset.Name, response.StatusCode, body)
+		}
+	}
+
+	response, _ := get(t, server.URL+"/releases?per_page=2")
+	link := response.Header.Get("Link")
+	if !strings.Contains(link, `rel="next"`) || !strings.Contains(link, `rel="last"`) {
+		t.Errorf("expected pagination links but got: %s", link)
+	}
+
+	tests := []struct {
+		Path   string
+		Status int
+	}{
+		{Path: "/releases/tags/4.3-stable", Status: http.StatusOK},
+		{Path: "/releases/tags/9.9-stable", Status: http.StatusNotFound},
+		{Path: "/releases?page=0", Status: http.StatusBadRequest},
+		{Path: "/download/" + godot.CACHE_FOLDER + "/missing.zip", Status: http.StatusNotFound},
+		{Path: "/download/other/Godot_v4.3-stable_linux.x86_64.zip", Status: http.StatusNotFound},
+		{Path: "/download/" + godot.CACHE_FOLDER + "/.Godot_v4.3-stable_linux.x86_64.zip.lock", Status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		response, body := get(t, server.URL+test.Path)
+		if response.StatusCode != test.Status {
+			t.Errorf("expected %d for '%s' but got %d: %s", test.Status, test.Path, response.StatusCode, body)
+		}
+	}
+}
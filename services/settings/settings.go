@@ -1,17 +1,16 @@
 package settings
 
 import (
-	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/bashmills/gevm/config"
-	"github.com/bashmills/gevm/internal/utils"
+	"github.com/bashmills/gevm/errs"
 )
 
-var ErrNotFound = errors.New("not found")
-
 type Service struct {
 	Config *config.Config
 }
@@ -33,28 +32,44 @@ func (s *Service) Reset() error {
 	return nil
 }
 
-func (s *Service) List() error {
-	err := s.iterateFields(func(field reflect.Value, name string) error {
-		utils.Printlnf("%s = %s", name, field.String())
+func (s *Service) Keys() []string {
+	var keys []string
+	s.iterateFields(func(field reflect.Value, name string) error {
+		keys = append(keys, name)
 		return nil
 	})
-	if err != nil {
-		return fmt.Errorf("failed to iterate fields: %w", err)
-	}
 
-	return nil
+	return keys
+}
+
+func (s *Service) Settings() map[string]string {
+	settings := map[string]string{}
+	s.iterateFields(func(field reflect.Value, name string) error {
+		settings[name] = formatField(field)
+		return nil
+	})
+
+	return settings
 }
 
 func (s *Service) Set(key string, value string) error {
 	err := s.findField(key, func(field reflect.Value, name string) error {
-		utils.Printlnf("%s = %s => %s", name, field.String(), value)
-		field.SetString(value)
+		previous := reflect.New(field.Type()).Elem()
+		previous.Set(field)
+
+		err := setField(field, value)
+		if err != nil {
+			return fmt.Errorf("cannot set value: %w", err)
+		}
+
+		err = s.Config.Validate()
+		if err != nil {
+			field.Set(previous)
+			return err
+		}
+
 		return nil
 	})
-	if err == ErrNotFound {
-		s.Config.Logger.Error("Settings key '%s' not found. Use 'gevm settings list' to see available settings.", key)
-		return nil
-	}
 	if err != nil {
 		return fmt.Errorf("failed to find field: %w", err)
 	}
@@ -67,25 +82,21 @@ func (s *Service) Set(key string, value string) error {
 	return nil
 }
 
-func (s *Service) Get(key string) error {
+func (s *Service) Get(key string) (string, error) {
+	var value string
 	err := s.findField(key, func(field reflect.Value, name string) error {
-		utils.Printlnf("%s = %s", name, field.String())
+		value = formatField(field)
 		return nil
 	})
-	if err == ErrNotFound {
-		s.Config.Logger.Error("Settings key '%s' not found. Use 'gevm settings list' to see available settings.", key)
-		return nil
-	}
 	if err != nil {
-		return fmt.Errorf("failed to find field: %w", err)
+		return "", fmt.Errorf("failed to find field: %w", err)
 	}
 
-	return nil
+	return value, nil
 }
 
-func (s *Service) Path() error {
-	utils.Printlnf(s.Config.ConfigPath)
-	return nil
+func (s *Service) Path() string {
+	return s.Config.ConfigPath
 }
 
 func (s *Service) iterateFields(callback func(reflect.Value, string) error) error {
@@ -128,12 +139,57 @@ func (s *Service) findField(key string, callback func(reflect.Value, string) err
 	}
 
 	if !found {
-		return ErrNotFound
+		return fmt.Errorf("%w: %s", errs.ErrSettingNotFound, key)
+	}
+
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	default:
+		return fmt.Errorf("unsupported kind: %s", field.Kind())
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		result, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool: %w", err)
+		}
+
+		field.SetBool(result)
+	case reflect.Int:
+		result, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int: %w", err)
+		}
+
+		field.SetInt(int64(result))
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice kind: %s", field.Type().Elem().Kind())
+		}
+
+		field.Set(reflect.ValueOf(filepath.SplitList(value)))
 	}
 
 	return nil
 }
 
+func formatField(field reflect.Value) string {
+	switch field.Kind() {
+	default:
+		return fmt.Sprint(field.Interface())
+	case reflect.Slice:
+		var values []string
+		for i := 0; i < field.Len(); i++ {
+			values = append(values, fmt.Sprint(field.Index(i).Interface()))
+		}
+
+		return strings.Join(values, string(filepath.ListSeparator))
+	}
+}
+
 func New(config *config.Config) *Service {
 	return &Service{
 		Config: config,
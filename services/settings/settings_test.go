@@ -0,0 +1,32 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/bashmills/gevm/internal/fixtures"
+)
+
+func TestSetValidates(t *testing.T) {
+	config := fixtures.NewConfig(t, t.TempDir())
+	service := New(config)
+
+	err := service.Set("lock-timeout", "2m")
+	if err != nil {
+		t.Fatalf("cannot set lock timeout: %s", err)
+	}
+
+	for _, test := range []struct{ Key, Value string }{
+		{Key: "lock-timeout", Value: "foo"},
+		{Key: "prune-older-than", Value: "soon"},
+		{Key: "prune-max-size", Value: "big"},
+	} {
+		err = service.Set(test.Key, test.Value)
+		if err == nil {
+			t.Errorf("expected '%s' to be rejected for '%s'", test.Value, test.Key)
+		}
+	}
+
+	if config.LockTimeout != "2m" || len(config.PruneOlderThan) > 0 || len(config.PruneMaxSize) > 0 {
+		t.Errorf("rejected values were kept: %s %s %s", config.LockTimeout, config.PruneOlderThan, config.PruneMaxSize)
+	}
+}
@@ -0,0 +1,307 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bashmills/gevm/config"
+	"github.com/bashmills/gevm/errs"
+	"github.com/bashmills/gevm/internal/environment"
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/repository"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/cache"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+type InstallationChecker interface {
+	IsInstalled(semver semver.Semver) (bool, error)
+	ListInstalled() ([]godot.Installation, error)
+}
+
+type ExportTemplatesChecker interface {
+	Exists(semver semver.Semver) (bool, error)
+}
+
+type ArchiveLister interface {
+	Archives() ([]cache.Archive, error)
+}
+
+type Filter struct {
+	All            bool
+	Mono           bool
+	Constraints    []semver.Constraint
+	Labels         []string
+	Since          time.Time
+	Limit          int
+	LatestPerMinor bool
+	Installed      bool
+	NotInstalled   bool
+}
+
+type Release struct {
+	Relver     semver.Relver
+	Published  time.Time
+	Notes      string
+	URL        string
+	Prerelease bool
+	Standard   repository.Download
+	Mono       repository.Download
+}
+
+type Status struct {
+	Installed       bool
+	ExportTemplates bool
+	Cached          bool
+	Newer           bool
+}
+
+type Service struct {
+	Environment            *environment.Environment
+	InstallationChecker    InstallationChecker
+	ExportTemplatesChecker ExportTemplatesChecker
+	ArchiveLister          ArchiveLister
+	Config                 *config.Config
+}
+
+func (s *Service) Available(ctx context.Context, filter Filter) ([]repository.Download, error) {
+	downloads, err := s.Environment.FetchDownloads(ctx, filter.Mono)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch environment downloads: %w", err)
+	}
+
+	var result []repository.Download
+	for _, download := range downloads {
+		installed, err := s.isInstalled(download.Relver, filter.Mono)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check installation: %w", err)
+		}
+
+		if !matches(filter, download.Relver, download.Published, installed) {
+			continue
+		}
+
+		result = append(result, download)
+	}
+
+	return reduce(filter, result, func(download repository.Download) semver.Relver { return download.Relver }), nil
+}
+
+func (s *Service) Combined(ctx context.Context, filter Filter) ([]Release, error) {
+	releases, err := s.releases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch releases: %w", err)
+	}
+
+	var result []Release
+	for _, release := range releases {
+		standardInstalled, err := s.isInstalled(release.Relver, false)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check installation: %w", err)
+		}
+
+		monoInstalled, err := s.isInstalled(release.Relver, true)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check installation: %w", err)
+		}
+
+		if !matches(filter, release.Relver, release.Published, standardInstalled || monoInstalled) {
+			continue
+		}
+
+		result = append(result, release)
+	}
+
+	return reduce(filter, result, func(release Release) semver.Relver { return release.Relver }), nil
+}
+
+func (s *Service) Info(ctx context.Context, relver semver.Relver) (*Release, error) {
+	standard, err := s.Environment.FetchDownload(ctx, relver, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch environment download: %w", err)
+	}
+
+	release := newRelease(*standard)
+	release.Standard = *standard
+
+	mono, err := s.Environment.FetchDownload(ctx, relver, true)
+	if !errors.Is(err, errs.ErrVersionNotFound) && err != nil {
+		return nil, fmt.Errorf("cannot fetch environment download: %w", err)
+	}
+
+	if mono != nil {
+		release.Mono = *mono
+	}
+
+	return &release, nil
+}
+
+func (s *Service) Statuses(semvers []semver.Semver, target platform.Platform) ([]Status, error) {
+	installations, err := s.InstallationChecker.ListInstalled()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list installations: %w", err)
+	}
+
+	archives, err := s.ArchiveLister.Archives()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cached archives: %w", err)
+	}
+
+	var statuses []Status
+	for _, semver := range semvers {
+		installed := slices.ContainsFunc(installations, func(installation godot.Installation) bool {
+			return installation.Semver.Equal(semver) && installation.Semver.Mono == semver.Mono
+		})
+
+		exportTemplates, err := s.ExportTemplatesChecker.Exists(semver)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check export templates: %w", err)
+		}
+
+		cached := slices.ContainsFunc(archives, func(archive cache.Archive) bool {
+			if archive.Folder != godot.CACHE_FOLDER || !slices.Contains(archive.Platforms, target) {
+				return false
+			}
+
+			return archive.Semver.Equal(semver) && archive.Semver.Mono == semver.Mono
+		})
+
+		newer := !installed && slices.ContainsFunc(installations, func(installation godot.Installation) bool {
+			version := installation.Semver.Relver.Version
+			if version.Major != semver.Relver.Version.Major || version.Minor != semver.Relver.Version.Minor {
+				return false
+			}
+
+			return installation.Semver.Mono == semver.Mono && semver.Relver.Greater(installation.Semver.Relver)
+		})
+
+		statuses = append(statuses, Status{
+			Installed:       installed,
+			ExportTemplates: exportTemplates,
+			Cached:          cached,
+			Newer:           newer,
+		})
+	}
+
+	return statuses, nil
+}
+
+func (s *Service) releases(ctx context.Context) ([]Release, error) {
+	standards, err := s.Environment.FetchDownloads(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch environment downloads: %w", err)
+	}
+
+	monos, err := s.Environment.FetchDownloads(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch environment downloads: %w", err)
+	}
+
+	var releases []Release
+	for _, standard := range standards {
+		releases = append(releases, newRelease(standard))
+		releases[len(releases)-1].Standard = standard
+	}
+
+	for _, mono := range monos {
+		index := slices.IndexFunc(releases, func(release Release) bool { return release.Relver.Equal(mono.Relver) })
+		if index < 0 {
+			releases = append(releases, newRelease(mono))
+			index = len(releases) - 1
+		}
+
+		releases[index].Mono = mono
+	}
+
+	slices.SortFunc(releases, func(a Release, b Release) int { return a.Relver.Compare(b.Relver) })
+
+	return releases, nil
+}
+
+func (s *Service) isInstalled(relver semver.Relver, mono bool) (bool, error) {
+	return s.InstallationChecker.IsInstalled(semver.Semver{
+		Relver: relver,
+		Mono:   mono,
+	})
+}
+
+func newRelease(download repository.Download) Release {
+	return Release{
+		Relver:     download.Relver,
+		Published:  download.Published,
+		Notes:      download.Notes,
+		URL:        download.URL,
+		Prerelease: download.Prerelease,
+	}
+}
+
+func matches(filter Filter, relver semver.Relver, published time.Time, installed bool) bool {
+	if len(filter.Labels) > 0 {
+		if !slices.Contains(filter.Labels, relver.Release.Label) {
+			return false
+		}
+	} else if !relver.IsStable() && !filter.All {
+		return false
+	}
+
+	if len(filter.Constraints) > 0 && !slices.ContainsFunc(filter.Constraints, func(constraint semver.Constraint) bool { return constraint.Matches(relver.Version) }) {
+		return false
+	}
+
+	// Custom fetchers may not know publish dates so unknown dates are kept
+	if !filter.Since.IsZero() && !published.IsZero() && published.Before(filter.Since) {
+		return false
+	}
+
+	if filter.Installed && !installed {
+		return false
+	}
+
+	if filter.NotInstalled && installed {
+		return false
+	}
+
+	return true
+}
+
+func reduce[T any](filter Filter, items []T, relverOf func(T) semver.Relver) []T {
+	if filter.LatestPerMinor {
+		var result []T
+		for _, item := range items {
+			relver := relverOf(item)
+			index := slices.IndexFunc(result, func(other T) bool {
+				version := relverOf(other).Version
+				return version.Major == relver.Version.Major && version.Minor == relver.Version.Minor
+			})
+			if index < 0 {
+				result = append(result, item)
+				continue
+			}
+
+			if relver.Greater(relverOf(result[index])) {
+				result[index] = item
+			}
+		}
+
+		items = result
+	}
+
+	if filter.Limit > 0 && len(items) > filter.Limit {
+		items = items[len(items)-filter.Limit:]
+	}
+
+	return items
+}
+
+func New(environment *environment.Environment, installationChecker InstallationChecker, exportTemplatesChecker ExportTemplatesChecker, archiveLister ArchiveLister, config *config.Config) *Service {
+	return &Service{
+		Environment:            environment,
+		InstallationChecker:    installationChecker,
+		ExportTemplatesChecker: exportTemplatesChecker,
+		ArchiveLister:          archiveLister,
+		Config:                 config,
+	}
+}
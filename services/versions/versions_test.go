@@ -0,0 +1,87 @@
+package versions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bashmills/gevm/platform"
+	"github.com/bashmills/gevm/semver"
+	"github.com/bashmills/gevm/services/cache"
+	"github.com/bashmills/gevm/services/exporttemplates"
+	"github.com/bashmills/gevm/services/godot"
+)
+
+func TestMatchesSince(t *testing.T) {
+	relver, _ := semver.ParseRelver("4.3-stable")
+	since := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Published time.Time
+		Expected  bool
+	}{
+		{Name: "before", Published: since.AddDate(0, 0, -1), Expected: false},
+		{Name: "on", Published: since, Expected: true},
+		{Name: "after", Published: since.AddDate(0, 0, 1), Expected: true},
+		{Name: "unknown", Published: time.Time{}, Expected: true},
+	}
+
+	for _, test := range tests {
+		result := matches(Filter{Since: since}, relver, test.Published, false)
+		if result != test.Expected {
+			t.Errorf("expected %t for '%s' but got %t", test.Expected, test.Name, result)
+		}
+	}
+}
+
+type fakeInstallations struct{}
+
+func (fakeInstallations) IsInstalled(semver semver.Semver) (bool, error) {
+	return false, nil
+}
+
+func (fakeInstallations) ListInstalled() ([]godot.Installation, error) {
+	return nil, nil
+}
+
+func (fakeInstallations) Exists(semver semver.Semver) (bool, error) {
+	return false, nil
+}
+
+type fakeArchives []cache.Archive
+
+func (f fakeArchives) Archives() ([]cache.Archive, error) {
+	return f, nil
+}
+
+func TestStatusesCached(t *testing.T) {
+	version := semver.Maybe("4.3", "stable", false)
+	service := &Service{
+		InstallationChecker:    fakeInstallations{},
+		ExportTemplatesChecker: fakeInstallations{},
+		ArchiveLister: fakeArchives{
+			{Folder: godot.CACHE_FOLDER, Semver: version, Platforms: []platform.Platform{platform.WindowsAmd64}},
+			{Folder: exporttemplates.CACHE_FOLDER, Semver: version, Platforms: []platform.Platform{platform.ExportTemplates}},
+		},
+	}
+
+	tests := []struct {
+		Platform platform.Platform
+		Expected bool
+	}{
+		{Platform: platform.WindowsAmd64, Expected: true},
+		{Platform: platform.LinuxAmd64, Expected: false},
+		{Platform: platform.ExportTemplates, Expected: false},
+	}
+
+	for _, test := range tests {
+		statuses, err := service.Statuses([]semver.Semver{version}, test.Platform)
+		if err != nil {
+			t.Fatalf("cannot determine statuses: %s", err)
+		}
+
+		if statuses[0].Cached != test.Expected {
+			t.Errorf("expected cached %t for '%s' but got %t", test.Expected, test.Platform, statuses[0].Cached)
+		}
+	}
+}